@@ -1,9 +1,11 @@
 package googletrends
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestExploreResponseSort(t *testing.T) {
@@ -99,6 +101,138 @@ func TestExploreResponseGetWidgetsByType(t *testing.T) {
 	assert.Equal(t, 1, len(timeseries))
 }
 
+func TestExploreResponseMerge(t *testing.T) {
+	t.Parallel()
+
+	first := ExploreResponse{
+		{ID: "RELATED_QUERIES_1", Token: "q1"},
+		{ID: "TIMESERIES", Token: "ts"},
+		{ID: "RELATED_QUERIES_0", Token: "q0"},
+	}
+	second := ExploreResponse{
+		{ID: "TIMESERIES", Token: "ts", Title: "duplicate"},
+		{ID: "TIMESERIES", Token: "ts2", Title: "duplicate ID"},
+		{ID: "RELATED_TOPICS_0", Token: "t0"},
+		{ID: "GEO_MAP", Token: ""},
+		{ID: "GEO_MAP", Token: ""},
+	}
+
+	merged := first.Merge(second)
+
+	ids := make([]string, 0, len(merged))
+	for _, w := range merged {
+		ids = append(ids, w.ID)
+	}
+	assert.Equal(t, []string{"TIMESERIES", "GEO_MAP", "RELATED_QUERIES_0", "RELATED_TOPICS_0", "RELATED_QUERIES_1"}, ids)
+	assert.False(t, merged.HasDuplicates())
+
+	// first occurrence of a duplicate token or ID wins
+	timeseries := merged.GetWidgetsByType(IntOverTimeWidgetID)
+	require.Len(t, timeseries, 1)
+	assert.Empty(t, timeseries[0].Title)
+
+	// inputs are left untouched
+	assert.Equal(t, "RELATED_QUERIES_1", first[0].ID)
+	assert.Len(t, first, 3)
+	assert.Len(t, second, 5)
+}
+
+func TestExploreWidgetResolvedTimeRange(t *testing.T) {
+	t.Parallel()
+
+	const body = `[` +
+		`{"id":"TIMESERIES","request":{"time":"2023-10-14 2024-10-14","comparisonItem":[` +
+		`{"geo":{"country":"US"},"time":"2023-10-14 2024-10-14","originalTimeRangeForExploreUrl":"today 12-m"}]}},` +
+		`{"id":"GEO_MAP","request":{"comparisonItem":[` +
+		`{"time":"2023-10-14 2024-10-14","originalTimeRangeForExploreUrl":"today 12-m"}]}},` +
+		`{"id":"RELATED_QUERIES","request":{"restriction":{"time":"2023-10-14 2024-10-14",` +
+		`"originalTimeRangeForExploreUrl":"today 12-m"}}},` +
+		`{"id":"RELATED_TOPICS","request":{}},` +
+		`{"id":"GEO_MAP"}]`
+
+	var widgets ExploreResponse
+	require.NoError(t, json.Unmarshal([]byte(body), &widgets))
+	require.Len(t, widgets, 5)
+
+	for _, w := range widgets[:3] {
+		assert.Equal(t, "2023-10-14 2024-10-14", w.ResolvedTimeRange(), w.ID)
+	}
+	assert.Empty(t, widgets[3].ResolvedTimeRange())
+	assert.Empty(t, widgets[4].ResolvedTimeRange())
+}
+
+func TestExploreWidgetKeywordIndex(t *testing.T) {
+	t.Parallel()
+
+	item := func(keyword string) *WidgetComparisonItem {
+		return &WidgetComparisonItem{ComplexKeywordsRestriction: KeywordsRestriction{
+			Keyword: []*KeywordRestriction{{Type: "BROAD", Value: keyword}},
+		}}
+	}
+
+	timeseries := &ExploreWidget{
+		ID: string(IntOverTimeWidgetID),
+		Request: &WidgetResponse{CompItem: []*WidgetComparisonItem{
+			item("golang"), nil, item("Python"), item("/m/0dsbpg6"),
+		}},
+	}
+	related := &ExploreWidget{
+		ID:      "RELATED_QUERIES_2",
+		Request: &WidgetResponse{Restriction: *item("python")},
+	}
+
+	tests := []struct {
+		name          string
+		widget        *ExploreWidget
+		keyword       string
+		expectedIndex int
+		expectedOK    bool
+	}{
+		{name: "first item", widget: timeseries, keyword: "golang", expectedIndex: 0, expectedOK: true},
+		{name: "case and spaces ignored", widget: timeseries, keyword: " python ", expectedIndex: 2, expectedOK: true},
+		{name: "entity mid", widget: timeseries, keyword: "/m/0dsbpg6", expectedIndex: 3, expectedOK: true},
+		{name: "unknown keyword", widget: timeseries, keyword: "rust"},
+		{name: "related widget order", widget: related, keyword: "Python", expectedIndex: 2, expectedOK: true},
+		{name: "related widget other keyword", widget: related, keyword: "golang"},
+		{name: "no request", widget: &ExploreWidget{ID: "GEO_MAP"}, keyword: "golang"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i, ok := tt.widget.KeywordIndex(tt.keyword)
+
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expectedIndex, i)
+		})
+	}
+}
+
+func TestExploreResponseDeduplicate(t *testing.T) {
+	t.Parallel()
+
+	widgets := ExploreResponse{
+		{ID: "TIMESERIES", Token: "ts"},
+		{ID: "RELATED_QUERIES", Token: "q"},
+		nil,
+		{ID: "TIMESERIES", Token: "ts2"},
+		{ID: "GEO_MAP", Token: "geo"},
+	}
+
+	assert.True(t, widgets.HasDuplicates())
+
+	deduped := widgets.Deduplicate()
+	assert.False(t, deduped.HasDuplicates())
+	require.Len(t, deduped, 3)
+	assert.Equal(t, "ts", deduped[0].Token)
+	assert.Len(t, deduped.GetWidgetsByType(IntOverTimeWidgetID), 1)
+
+	// the receiver is left untouched
+	assert.Len(t, widgets, 5)
+
+	assert.False(t, ExploreResponse(nil).HasDuplicates())
+	assert.Empty(t, ExploreResponse(nil).Deduplicate())
+}
+
 func TestWidgetTypes(t *testing.T) {
 	t.Parallel()
 
@@ -261,6 +395,49 @@ func TestRankedKeyword(t *testing.T) {
 	assert.Equal(t, "/m/09gbxjr", keyword.Topic.Mid)
 }
 
+func TestRankedKeywordFullLink(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		link     string
+		expected string
+	}{
+		{
+			name:     "relative link gets default host",
+			link:     "/trends/explore?q=golang+tutorial&date=today+12-m",
+			expected: "https://trends.google.com/trends/explore?q=golang+tutorial&date=today+12-m",
+		},
+		{
+			name:     "custom base with trailing slash",
+			base:     "https://trends.example.com/",
+			link:     "/trends/explore?q=golang",
+			expected: "https://trends.example.com/trends/explore?q=golang",
+		},
+		{
+			name:     "absolute link unchanged",
+			link:     "https://trends.google.com/trends/explore?q=golang",
+			expected: "https://trends.google.com/trends/explore?q=golang",
+		},
+		{
+			name:     "empty link",
+			link:     "",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := useMockClient(t, nil)
+			if tt.base != "" {
+				WithLinkBase(tt.base)(c)
+			}
+
+			keyword := &RankedKeyword{Link: tt.link}
+			assert.Equal(t, tt.expected, keyword.FullLink())
+		})
+	}
+}
+
 func TestKeywordTopic(t *testing.T) {
 	t.Parallel()
 
@@ -274,3 +451,112 @@ func TestKeywordTopic(t *testing.T) {
 	assert.Equal(t, "Go", topic.Title)
 	assert.Equal(t, "Programming language", topic.Type)
 }
+
+func TestVersion(t *testing.T) {
+	t.Parallel()
+
+	assert.Regexp(t, `^\d+\.\d+\.\d+$`, Version())
+}
+
+func TestCapabilities(t *testing.T) {
+	t.Parallel()
+
+	caps := Capabilities()
+
+	assert.True(t, caps[CapabilityBatchDaily])
+	assert.True(t, caps[CapabilityExplore])
+	assert.False(t, caps[CapabilityRealtime])
+	assert.Contains(t, caps, CapabilityLegacyCategories)
+
+	// callers can't modify the reported capabilities
+	caps[CapabilityRealtime] = true
+	assert.False(t, Capabilities()[CapabilityRealtime])
+}
+
+func TestExploreRequestCacheKey(t *testing.T) {
+	t.Parallel()
+
+	newRequest := func(items ...*ComparisonItem) *ExploreRequest {
+		return &ExploreRequest{ComparisonItems: items, Category: 31}
+	}
+	golang := &ComparisonItem{Keyword: "golang", Geo: "US", Time: "today 12-m"}
+	rust := &ComparisonItem{Keyword: "rust", Geo: "US", Time: "today 12-m"}
+
+	base := newRequest(golang, rust).CacheKey()
+	assert.Len(t, base, 64)
+
+	tests := []struct {
+		name    string
+		request *ExploreRequest
+		same    bool
+	}{
+		{
+			name:    "equal request",
+			request: newRequest(&ComparisonItem{Keyword: "golang", Geo: "US", Time: "today 12-m"}, rust),
+			same:    true,
+		},
+		{
+			name:    "normalized fields",
+			request: newRequest(&ComparisonItem{Keyword: "golang", Geo: "usa", Time: "today+12-m"}, rust),
+			same:    true,
+		},
+		{
+			name:    "keyword is taken as is",
+			request: newRequest(&ComparisonItem{Keyword: " golang", Geo: "US", Time: "today 12-m"}, rust),
+			same:    false,
+		},
+		{
+			name:    "detected keyword type",
+			request: newRequest(&ComparisonItem{Keyword: "golang", Geo: "US", Time: "today 12-m", KeywordType: "QUERY"}, rust),
+			same:    true,
+		},
+		{
+			name:    "keyword type matters",
+			request: newRequest(&ComparisonItem{Keyword: "golang", Geo: "US", Time: "today 12-m", KeywordType: "ENTITY"}, rust),
+			same:    false,
+		},
+		{
+			name:    "order matters",
+			request: newRequest(rust, golang),
+			same:    false,
+		},
+		{
+			name:    "category matters",
+			request: &ExploreRequest{ComparisonItems: []*ComparisonItem{golang, rust}},
+			same:    false,
+		},
+		{
+			name:    "property matters",
+			request: &ExploreRequest{ComparisonItems: []*ComparisonItem{golang, rust}, Category: 31, Property: "youtube"},
+			same:    false,
+		},
+		{
+			name:    "time matters",
+			request: newRequest(&ComparisonItem{Keyword: "golang", Geo: "US", Time: "today 5-y"}, rust),
+			same:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.same, tt.request.CacheKey() == base)
+		})
+	}
+
+	t.Run("request-level geo", func(t *testing.T) {
+		shared := &ExploreRequest{
+			ComparisonItems: []*ComparisonItem{{Keyword: "golang", Time: "today 12-m"}, rust},
+			Category:        31,
+			Geo:             "US",
+		}
+
+		assert.Equal(t, base, shared.CacheKey())
+	})
+
+	t.Run("request is not modified", func(t *testing.T) {
+		item := &ComparisonItem{Keyword: " golang ", Geo: "usa", Time: "today+12-m"}
+		newRequest(item).CacheKey()
+
+		assert.Equal(t, &ComparisonItem{Keyword: " golang ", Geo: "usa", Time: "today+12-m"}, item)
+	})
+}
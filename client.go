@@ -1,7 +1,9 @@
 package googletrends
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,8 +11,10 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 // HTTP header and content type constants used for API requests.
@@ -30,16 +34,33 @@ const (
 	// headerKeyUserAgent is the HTTP header key for User-Agent.
 	headerKeyUserAgent = "User-Agent"
 
+	// headerKeyClientData is the HTTP header key for the browser X-Client-Data header.
+	headerKeyClientData = "X-Client-Data"
+
 	// contentTypeJSON is the MIME type for JSON content.
 	contentTypeJSON = "application/json"
 
 	// contentTypeForm is the MIME type for URL-encoded form data.
 	contentTypeForm = "application/x-www-form-urlencoded;charset=UTF-8"
 
-	// defaultUserAgent mimics a real browser to avoid rate limiting.
+	// contentTypeHTML is the MIME type of HTML pages, such as the consent page.
+	contentTypeHTML = "text/html"
+
+	// defaultUserAgent mimics a real browser to avoid rate limiting. See WithUserAgent.
 	defaultUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36"
 )
 
+// browserHeaders are the fetch metadata and client hint headers Chrome sends with
+// trends.google.com API calls, matching defaultUserAgent. See WithBrowserHeaders.
+var browserHeaders = map[string]string{
+	"Sec-Ch-Ua":          `"Chromium";v="142", "Google Chrome";v="142", "Not_A Brand";v="99"`,
+	"Sec-Ch-Ua-Mobile":   "?0",
+	"Sec-Ch-Ua-Platform": `"macOS"`,
+	"Sec-Fetch-Dest":     "empty",
+	"Sec-Fetch-Mode":     "cors",
+	"Sec-Fetch-Site":     "same-origin",
+}
+
 // HTTPDoer is an interface for making HTTP requests.
 // It abstracts the http.Client to allow for dependency injection and easier testing.
 //
@@ -69,6 +90,7 @@ type HTTPDoer interface {
 type gClient struct {
 	// httpClient is the underlying HTTP client used for requests.
 	// Defaults to http.DefaultClient but can be overridden with WithHTTPClient.
+	// It is nil while the options run unless an option set a custom client.
 	httpClient HTTPDoer
 
 	// defParams contains default query parameters applied to all requests.
@@ -86,10 +108,79 @@ type gClient struct {
 	// exploreLocs caches the location tree to avoid repeated API calls.
 	exploreLocs *ExploreLocTree
 
+	// km protects concurrent access to cookie.
+	km *sync.RWMutex
+
 	// cookie stores the session cookie received from rate-limited responses.
 	// This cookie is automatically sent with subsequent requests to avoid further rate limiting.
 	cookie string
 
+	// rm protects concurrent access to lastRateLimit.
+	rm *sync.RWMutex
+
+	// lastRateLimit is the time the last HTTP 429 response was received.
+	lastRateLimit time.Time
+
+	// consentCookie is the optional cookie sent with every request to skip the consent page.
+	consentCookie string
+
+	// clientData is the optional base64-encoded X-Client-Data header value.
+	// The header is omitted when empty.
+	clientData string
+
+	// accept is the Accept header value of GET requests.
+	accept string
+
+	// userAgent is the User-Agent header value of every request.
+	userAgent string
+
+	// tz is the timezone offset sent as the tz query parameter, in minutes west of UTC.
+	tz int
+
+	// headers are the extra headers set on every request, see WithBrowserHeaders.
+	headers http.Header
+
+	// skipEmptyWidgets makes Explore drop widgets without a request payload.
+	skipEmptyWidgets bool
+
+	// timeout bounds every request, including reading the body, when positive.
+	timeout time.Duration
+
+	// minTLSVersion is the minimum TLS version of the default HTTP client, if not 0.
+	minTLSVersion uint16
+
+	// linkBase is the host of the links built by RankedKeyword.FullLink and TrendingSearch.URL.
+	linkBase string
+
+	// requestModifier is called on every outgoing request, including retries,
+	// right before it is sent.
+	requestModifier func(*http.Request)
+
+	// responseValidator checks every successful response body before it is parsed.
+	responseValidator func(endpoint string, body []byte) error
+
+	// maxArticles caps the number of articles parsed per trending search.
+	// Zero means unlimited.
+	maxArticles int
+
+	// strict enables validation of explore requests before they are sent.
+	strict bool
+
+	// normalizeKeywords makes Explore trim and lowercase plain query keywords.
+	normalizeKeywords bool
+
+	// noCache disables caching of the category and location trees.
+	noCache bool
+
+	// explores memoizes Explore responses when set with WithExploreCache.
+	explores *exploreCache
+
+	// batchRPCID is the batch execute RPC ID requesting trending searches.
+	batchRPCID string
+
+	// responses records the most recent raw responses when set with WithResponseBuffer.
+	responses *responseBuffer
+
 	// debug enables verbose logging of requests and responses when true.
 	debug bool
 }
@@ -120,6 +211,553 @@ func WithHTTPClient(httpClient HTTPDoer) Option {
 	}
 }
 
+// WithDefaultParam returns an Option that adds a query parameter to the default
+// parameters of the client, or replaces the default value of key. Default parameters
+// are sent with the legacy endpoints, currently the legacy daily trends API behind
+// DailyMerged. Parameters set by a call itself, such as hl and geo, take precedence.
+//
+// The per-request parameters token and req can't have defaults; the option is
+// ignored for them.
+//
+// Example:
+//
+//	client := newGClient(WithDefaultParam("tz", "-120"))
+func WithDefaultParam(key, value string) Option {
+	return func(c *gClient) {
+		if key == paramToken || key == paramReq {
+			return
+		}
+
+		c.defParams.Set(key, value)
+	}
+}
+
+// LegacyParams holds the pagination parameters of the legacy endpoints, which are
+// sent as the cryptic fi, fs, ri and rs query parameters. Zero fields keep the
+// defaults (0, 0, 300 and 20).
+type LegacyParams struct {
+	// FirstItem is the index of the first item (fi).
+	FirstItem int
+
+	// FirstSort is the index of the first sort (fs).
+	FirstSort int
+
+	// ResultsIndex is the results index (ri).
+	ResultsIndex int
+
+	// ResultsSize is the number of results (rs).
+	ResultsSize int
+}
+
+// WithLegacyParams returns an Option that sets the pagination parameters of the
+// legacy endpoints from p, see WithDefaultParam.
+//
+// Example:
+//
+//	client := newGClient(WithLegacyParams(LegacyParams{ResultsSize: 50}))
+func WithLegacyParams(p LegacyParams) Option {
+	return func(c *gClient) {
+		for key, v := range map[string]int{
+			paramFirstItem:    p.FirstItem,
+			paramFirstSort:    p.FirstSort,
+			paramResultsIndex: p.ResultsIndex,
+			paramResultsSize:  p.ResultsSize,
+		} {
+			if v != 0 {
+				c.defParams.Set(key, strconv.Itoa(v))
+			}
+		}
+	}
+}
+
+// WithRoundTripperChain returns an Option that sends requests through a chain of
+// http.RoundTripper middleware, such as auth, tracing or caching layers.
+// Each middleware receives the next RoundTripper of the chain and returns one wrapping it;
+// the innermost one wraps http.DefaultTransport. Middleware run in the given order,
+// so the first one sees the request first and the response last.
+//
+// The option replaces the HTTP client with an http.Client using the composed transport,
+// so it overrides a preceding WithHTTPClient and is overridden by a following one.
+//
+// Example:
+//
+//	client := newGClient(WithRoundTripperChain(withAuth, withTracing))
+func WithRoundTripperChain(middleware ...func(next http.RoundTripper) http.RoundTripper) Option {
+	return func(c *gClient) {
+		rt := http.DefaultTransport
+		for i := len(middleware) - 1; i >= 0; i-- {
+			rt = middleware[i](rt)
+		}
+
+		c.httpClient = &http.Client{Transport: rt}
+	}
+}
+
+// WithMinTLSVersion returns an Option that makes the default HTTP client refuse
+// TLS versions older than v, e.g. tls.VersionTLS12. It uses a copy of
+// http.DefaultTransport, which is left unmodified.
+//
+// The option is a no-op when a custom HTTP client is set by WithHTTPClient or
+// WithRoundTripperChain, in either order; configure TLS on that client instead.
+// It combines with WithTimeout.
+//
+// Example:
+//
+//	client := newGClient(WithMinTLSVersion(tls.VersionTLS12))
+func WithMinTLSVersion(v uint16) Option {
+	return func(c *gClient) {
+		c.minTLSVersion = v
+	}
+}
+
+// WithSkipEmptyWidgets returns an Option that makes Explore drop widgets whose request
+// payload is null, which Google occasionally returns. Without it such widgets are kept,
+// and the data functions return ErrMissingRequest for them.
+//
+// Example:
+//
+//	client := newGClient(WithSkipEmptyWidgets())
+func WithSkipEmptyWidgets() Option {
+	return func(c *gClient) {
+		c.skipEmptyWidgets = true
+	}
+}
+
+// WithTimeout returns an Option that bounds every request to d, on top of the
+// deadline of the call context: the context of each request is wrapped with d.
+// When the package uses its own default HTTP client, d is also set as the
+// http.Client Timeout, which covers the entire request including reading the
+// response body.
+//
+// Like WithMinTLSVersion, the client timeout is not set on a custom HTTP client set
+// by WithHTTPClient or WithRoundTripperChain; the context timeout still applies.
+// It combines with WithMinTLSVersion in either order. A non-positive d disables
+// the timeout.
+//
+// Example:
+//
+//	client := newGClient(WithTimeout(30 * time.Second))
+func WithTimeout(d time.Duration) Option {
+	return func(c *gClient) {
+		if d < 0 {
+			d = 0
+		}
+		c.timeout = d
+	}
+}
+
+// WithClientData returns an Option that attaches the X-Client-Data header to every request.
+// Some Google Trends endpoints respond differently to clients that don't send it,
+// so setting it can help when requests are blocked or return unexpected data.
+//
+// The option is not required. The value is a base64 string that Chrome sends with
+// requests to Google domains; copy it from the request headers of any
+// trends.google.com call in the browser devtools Network tab. Passing an empty
+// string removes the header again.
+//
+// Example:
+//
+//	client := newGClient(WithClientData("CIa2yQEIpLbJAQ..."))
+func WithClientData(b64 string) Option {
+	return func(c *gClient) {
+		c.clientData = b64
+	}
+}
+
+// WithAccept returns an Option that overrides the Accept header of API requests,
+// "application/json" by default. Some proxies and gateways reject the JSON-only
+// value; use e.g. WithAccept("*/*") behind them. An empty value keeps the default.
+//
+// Example:
+//
+//	client := newGClient(WithAccept("*/*"))
+func WithAccept(value string) Option {
+	return func(c *gClient) {
+		if len(value) != 0 {
+			c.accept = value
+		}
+	}
+}
+
+// WithTimezone returns an Option that sets the timezone offset of API requests, in
+// minutes west of UTC as Google expects it: 300 for US Eastern (UTC-5), -60 for
+// Central European Time (UTC+1). Google formats the FormattedTime and
+// FormattedAxisTime fields of timeline data in this timezone; the default is 0 (UTC).
+// The offset also replaces the tz default parameter of the legacy endpoints.
+//
+// Example:
+//
+//	client := newGClient(WithTimezone(300))
+func WithTimezone(offsetMinutes int) Option {
+	return func(c *gClient) {
+		c.tz = offsetMinutes
+		c.defParams.Set(paramTZ, strconv.Itoa(offsetMinutes))
+	}
+}
+
+// WithUserAgent returns an Option that overrides the User-Agent header of API requests,
+// which by default mimics a desktop Chrome build. A pool of clients with different
+// user agents draws fewer 429 responses than one heavily used value. When combined
+// with WithBrowserHeaders, override the Sec-Ch-Ua headers to match ua as well.
+// An empty value keeps the default.
+//
+// Example:
+//
+//	client := newGClient(WithUserAgent("MyBot/1.0"))
+func WithUserAgent(ua string) Option {
+	return func(c *gClient) {
+		if len(ua) != 0 {
+			c.userAgent = ua
+		}
+	}
+}
+
+// WithConsentCookie returns an Option that sends the given cookie with every request,
+// e.g. "SOCS=CAI...". Google redirects clients from some regions (notably the EU) to a
+// consent page until consent is given, which makes calls fail with ErrConsentRequired.
+//
+// Copy the SOCS or CONSENT cookie of trends.google.com from the browser devtools after
+// accepting the consent dialog once. It is sent together with the cookie the client
+// reuses after rate limiting.
+//
+// Example:
+//
+//	client := newGClient(WithConsentCookie("SOCS=CAISHAgBEhJnd3NfMjAyMzA4MTAtMF9SQzIaAmVuIAEaBgiAo_CmBg"))
+func WithConsentCookie(cookie string) Option {
+	return func(c *gClient) {
+		c.consentCookie = cookie
+	}
+}
+
+// WithBrowserHeaders returns an Option that attaches the Sec-Fetch-* and Sec-Ch-Ua*
+// headers Chrome sends with trends.google.com API calls, matching the default
+// User-Agent. Some Google endpoints treat requests without them as non-browser
+// clients and block them more eagerly.
+//
+// Headers in overrides replace the values of the default set, or are added to it,
+// and a header with no values is removed from it. Keys are canonicalized.
+//
+// Example:
+//
+//	client := newGClient(WithBrowserHeaders(http.Header{
+//	    "Sec-Ch-Ua-Platform": {`"Windows"`},
+//	    "Sec-Ch-Ua-Mobile":   nil,
+//	}))
+func WithBrowserHeaders(overrides ...http.Header) Option {
+	return func(c *gClient) {
+		h := make(http.Header, len(browserHeaders))
+		for k, v := range browserHeaders {
+			h.Set(k, v)
+		}
+
+		for _, o := range overrides {
+			for k, v := range o {
+				if len(v) == 0 {
+					h.Del(k)
+					continue
+				}
+				h[http.CanonicalHeaderKey(k)] = append([]string(nil), v...)
+			}
+		}
+
+		c.headers = h
+	}
+}
+
+// WithRequestModifier returns an Option that calls modify on every outgoing request
+// right before it is sent, including the retry after an HTTP 429 response.
+// Use it to compute per-request values such as signatures, timestamps or tracing headers,
+// which static options can't provide.
+//
+// The modifier runs after all headers set by the client, so it can override them.
+//
+// Example:
+//
+//	client := newGClient(WithRequestModifier(func(r *http.Request) {
+//	    r.Header.Set("X-Request-Time", strconv.FormatInt(time.Now().Unix(), 10))
+//	}))
+func WithRequestModifier(modify func(*http.Request)) Option {
+	return func(c *gClient) {
+		c.requestModifier = modify
+	}
+}
+
+// WithLinkBase returns an Option that sets the base URL of the links built by
+// RankedKeyword.FullLink, TrendingSearch.URL and RelatedToCSV, or by the Client
+// methods of the same purpose. Use it for self-hosted mirrors or proxies of Google
+// Trends. Defaults to "https://trends.google.com".
+//
+// Example:
+//
+//	client := newGClient(WithLinkBase("https://trends.example.com"))
+func WithLinkBase(base string) Option {
+	return func(c *gClient) {
+		c.linkBase = base
+	}
+}
+
+// WithResponseValidator returns an Option that runs validate on the body of every
+// HTTP 200 response before it is parsed. endpoint is the path of the request URL,
+// e.g. "/trends/api/explore". If validate returns an error, the request fails with
+// an error wrapping both ErrRequestFailed and the returned error.
+//
+// Use it to detect soft-blocks or schema drift in one place instead of per call site.
+// ValidateNotBlocked is a ready-made validator for captcha and block pages.
+//
+// Example:
+//
+//	client := newGClient(WithResponseValidator(ValidateNotBlocked))
+func WithResponseValidator(validate func(endpoint string, body []byte) error) Option {
+	return func(c *gClient) {
+		c.responseValidator = validate
+	}
+}
+
+// ValidateNotBlocked is a response validator for WithResponseValidator that rejects
+// HTML pages with ErrBlocked. Google Trends APIs answer with JSON behind an anti-XSSI
+// prefix, so an HTML body means a captcha, consent or "unusual traffic" page was
+// served instead of data.
+func ValidateNotBlocked(endpoint string, body []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("<")) {
+		return fmt.Errorf("%w: %s", ErrBlocked, endpoint)
+	}
+
+	return nil
+}
+
+// WithKeywordNormalization returns an Option that makes Explore trim and lowercase the
+// keywords of plain search queries before sending them. Google treats "Golang" and
+// "golang" as the same query but echoes the casing it was sent in some fields, so
+// mixed casings across comparison items can break joins on keywords downstream.
+// Entity MIDs such as "/m/09gbxjr" are case sensitive and left untouched. Only the
+// request sent is normalized; the ExploreRequest passed to Explore is not modified.
+//
+// Example:
+//
+//	client := newGClient(WithKeywordNormalization())
+func WithKeywordNormalization() Option {
+	return func(c *gClient) {
+		c.normalizeKeywords = true
+	}
+}
+
+// WithStrictValidation returns an Option that makes Explore validate the category and
+// property of every request with ValidateExploreCombo and return its error instead of
+// sending a request Google would answer with empty data.
+//
+// Example:
+//
+//	client := newGClient(WithStrictValidation())
+func WithStrictValidation() Option {
+	return func(c *gClient) {
+		c.strict = true
+	}
+}
+
+// WithMaxArticles returns an Option that keeps at most n articles per trending search
+// returned by DailyNew and DailyTrendingSearchNew. Extra articles are dropped while
+// parsing, so consumers that only show a few don't pay for the rest.
+// A value of 0 or less means unlimited, which is the default.
+//
+// Example:
+//
+//	client := newGClient(WithMaxArticles(3))
+func WithMaxArticles(n int) Option {
+	return func(c *gClient) {
+		c.maxArticles = n
+	}
+}
+
+// WithoutCache returns an Option that disables caching of the category and location
+// trees, so ExploreCategories and ExploreLocations fetch them on every call.
+// Use it in short-lived deployments, where the cache never pays off and only retains
+// memory, or in tests that must not share cached state.
+//
+// Without a cached category tree ValidateExploreCombo can't check that a category exists.
+//
+// Example:
+//
+//	client := newGClient(WithoutCache())
+func WithoutCache() Option {
+	return func(c *gClient) {
+		c.noCache = true
+	}
+}
+
+// WithBatchRPCID returns an Option that overrides the batch execute RPC ID DailyNew and
+// DailyTrendingSearchNew request trends with, "i0OFE" by default. Google has rotated
+// the ID before, which breaks these functions until the package is updated; this
+// option lets callers switch to the new ID right away. An empty id keeps the default.
+//
+// To find the current ID, open trends.google.com/trending in a browser, filter the
+// devtools Network tab by "batchexecute" and read the rpcids query parameter of the
+// request that loads the list of trends.
+//
+// Example:
+//
+//	googletrends.Configure(googletrends.WithBatchRPCID("i0OFE"))
+func WithBatchRPCID(id string) Option {
+	return func(c *gClient) {
+		if len(id) != 0 {
+			c.batchRPCID = id
+		}
+	}
+}
+
+// WithCategorySnapshot returns an Option that seeds the category cache with tree,
+// typically loaded with LoadCategories, so ExploreCategories never requests the picker
+// endpoint. It has no effect together with WithoutCache.
+//
+// Example:
+//
+//	client := newGClient(WithCategorySnapshot(categories))
+func WithCategorySnapshot(tree *ExploreCatTree) Option {
+	return func(c *gClient) {
+		c.setCategories(tree)
+	}
+}
+
+// WithResponseBuffer returns an Option that keeps the last n raw responses in memory,
+// exposed with RecentResponses. It records what Google actually returned, which helps
+// diagnose intermittent schema changes without turning on debug logging.
+//
+// Every response is recorded, including errors and rate-limited ones that were retried.
+// A value of 0 or less disables the buffer, which is the default.
+//
+// Example:
+//
+//	googletrends.Configure(googletrends.WithResponseBuffer(10))
+func WithResponseBuffer(n int) Option {
+	return func(c *gClient) {
+		c.responses = nil
+		if n > 0 {
+			c.responses = &responseBuffer{items: make([]RawResponse, 0, n), size: n}
+		}
+	}
+}
+
+// RawResponse is a response recorded by the buffer enabled with WithResponseBuffer.
+type RawResponse struct {
+	// Endpoint is the URL path the request was sent to, e.g. "/trends/api/explore".
+	Endpoint string
+
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// Body is the raw response body.
+	Body []byte
+}
+
+// responseBuffer is a concurrency-safe ring buffer of the most recent raw responses.
+type responseBuffer struct {
+	mu    sync.Mutex
+	items []RawResponse
+	size  int
+	next  int
+}
+
+// add records r, replacing the oldest response once the buffer is full.
+func (b *responseBuffer) add(r RawResponse) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.items) < b.size {
+		b.items = append(b.items, r)
+		return
+	}
+
+	b.items[b.next] = r
+	b.next = (b.next + 1) % b.size
+}
+
+// list returns the recorded responses from the oldest to the most recent.
+func (b *responseBuffer) list() []RawResponse {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	out := make([]RawResponse, 0, len(b.items))
+	out = append(out, b.items[b.next:]...)
+	out = append(out, b.items[:b.next]...)
+
+	return out
+}
+
+// WithExploreCache returns an Option that memoizes Explore responses for ttl, keyed by
+// the host language and ExploreRequest.CacheKey. Repeating an identical Explore within
+// ttl returns the cached widgets without an HTTP call, which helps dashboards that
+// refresh the same view stay below rate limits. Every call gets its own copy of the
+// widgets, so they can be used and modified independently.
+//
+// Failed requests are not cached. Concurrent identical calls that miss the cache all
+// send the request. A ttl of 0 or less disables the cache, which is the default.
+//
+// Example:
+//
+//	googletrends.Configure(googletrends.WithExploreCache(5 * time.Minute))
+func WithExploreCache(ttl time.Duration) Option {
+	return func(c *gClient) {
+		c.explores = nil
+		if ttl > 0 {
+			c.explores = newExploreCache(ttl)
+		}
+	}
+}
+
+// exploreCache is a concurrency-safe cache of raw Explore response bodies with expiry.
+// Bodies are cached rather than widgets so every hit decodes fresh widgets.
+type exploreCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]exploreEntry
+
+	// now returns the current time; it is replaced in tests.
+	now func() time.Time
+}
+
+// exploreEntry is a cached Explore response body and the time it expires at.
+type exploreEntry struct {
+	body    string
+	expires time.Time
+}
+
+// newExploreCache creates an empty explore cache whose entries expire after ttl.
+func newExploreCache(ttl time.Duration) *exploreCache {
+	return &exploreCache{ttl: ttl, entries: make(map[string]exploreEntry), now: time.Now}
+}
+
+// get returns the cached body for key, or false if there is none or it has expired.
+func (e *exploreCache) get(key string) (string, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	entry, ok := e.entries[key]
+	if !ok {
+		return "", false
+	}
+
+	if !e.now().Before(entry.expires) {
+		delete(e.entries, key)
+		return "", false
+	}
+
+	return entry.body, true
+}
+
+// set caches body for key and drops the expired entries.
+func (e *exploreCache) set(key, body string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.now()
+	for k, v := range e.entries {
+		if !now.Before(v.expires) {
+			delete(e.entries, k)
+		}
+	}
+
+	e.entries[key] = exploreEntry{body: body, expires: now.Add(e.ttl)}
+}
+
 // newGClient creates a new Google Trends client with default settings.
 // It initializes the client with default parameters, mutexes for thread-safe
 // caching, and applies any provided functional options.
@@ -136,19 +774,49 @@ func newGClient(opts ...Option) *gClient {
 	}
 
 	c := &gClient{
-		httpClient: http.DefaultClient,
 		defParams:  p,
+		linkBase:   gHost,
+		batchRPCID: rpcTrendingNow,
+		accept:     contentTypeJSON,
+		userAgent:  defaultUserAgent,
 		cm:         new(sync.RWMutex),
 		lm:         new(sync.RWMutex),
+		km:         new(sync.RWMutex),
+		rm:         new(sync.RWMutex),
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
+	if c.httpClient == nil {
+		c.httpClient = c.defaultHTTPClient()
+	}
+
 	return c
 }
 
+// defaultHTTPClient returns the HTTP client used without a custom one, configured by
+// WithTimeout and WithMinTLSVersion. Without either it is http.DefaultClient; otherwise
+// a new client on a copy of http.DefaultTransport, which is left unmodified.
+func (c *gClient) defaultHTTPClient() HTTPDoer {
+	if c.timeout <= 0 && c.minTLSVersion == 0 {
+		return http.DefaultClient
+	}
+
+	hc := &http.Client{Timeout: c.timeout}
+	if c.minTLSVersion != 0 {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = new(tls.Config)
+		}
+		transport.TLSClientConfig.MinVersion = c.minTLSVersion
+		hc.Transport = transport
+	}
+
+	return hc
+}
+
 // defaultParams returns a deep copy of the client's default URL parameters.
 // This ensures that modifications to the returned map don't affect the original.
 func (c *gClient) defaultParams() url.Values {
@@ -162,8 +830,12 @@ func (c *gClient) defaultParams() url.Values {
 }
 
 // getCategories returns the cached category tree in a thread-safe manner.
-// Returns nil if no categories have been cached yet.
+// Returns nil if no categories have been cached yet or caching is disabled.
 func (c *gClient) getCategories() *ExploreCatTree {
+	if c.noCache {
+		return nil
+	}
+
 	c.cm.RLock()
 	defer c.cm.RUnlock()
 	return c.exploreCats
@@ -171,14 +843,22 @@ func (c *gClient) getCategories() *ExploreCatTree {
 
 // setCategories stores the category tree in the cache in a thread-safe manner.
 func (c *gClient) setCategories(cats *ExploreCatTree) {
+	if c.noCache {
+		return
+	}
+
 	c.cm.Lock()
 	defer c.cm.Unlock()
 	c.exploreCats = cats
 }
 
 // getLocations returns the cached location tree in a thread-safe manner.
-// Returns nil if no locations have been cached yet.
+// Returns nil if no locations have been cached yet or caching is disabled.
 func (c *gClient) getLocations() *ExploreLocTree {
+	if c.noCache {
+		return nil
+	}
+
 	c.lm.RLock()
 	defer c.lm.RUnlock()
 	return c.exploreLocs
@@ -186,6 +866,10 @@ func (c *gClient) getLocations() *ExploreLocTree {
 
 // setLocations stores the location tree in the cache in a thread-safe manner.
 func (c *gClient) setLocations(locs *ExploreLocTree) {
+	if c.noCache {
+		return
+	}
+
 	c.lm.Lock()
 	defer c.lm.Unlock()
 	c.exploreLocs = locs
@@ -196,30 +880,41 @@ func (c *gClient) setLocations(locs *ExploreLocTree) {
 //
 // The method:
 //   - Creates a new request with the provided context
-//   - Adds Accept header for JSON content
+//   - Adds Accept header for JSON content (or the WithAccept value)
 //   - Includes any stored cookies from previous rate-limited responses
 //   - Logs request/response details when debug mode is enabled
 //   - Retries once with the cookie if rate limited (HTTP 429)
 //
 // Returns the response body as bytes or an error if the request fails.
 func (c *gClient) do(ctx context.Context, u *url.URL) ([]byte, error) {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
+
 	r, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", errCreateRequest, err)
 	}
 
-	r.Header.Add(headerKeyAccept, contentTypeJSON)
-	r.Header.Add(headerKeyUserAgent, defaultUserAgent)
+	r.Header.Add(headerKeyAccept, c.accept)
+	r.Header.Add(headerKeyUserAgent, c.userAgent)
 
-	if len(c.cookie) != 0 {
-		r.Header.Add(headerKeyCookie, c.cookie)
+	if len(c.clientData) != 0 {
+		r.Header.Add(headerKeyClientData, c.clientData)
+	}
+
+	for k, v := range c.headers {
+		r.Header[k] = append([]string(nil), v...)
+	}
+
+	if cookie := c.cookieHeader(); len(cookie) != 0 {
+		r.Header.Add(headerKeyCookie, cookie)
 	}
 
 	if c.debug {
 		log.Println("[Debug] Request with params: ", r.URL)
 	}
 
-	resp, err := c.httpClient.Do(r)
+	resp, err := c.send(r)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", errDoRequest, err)
 	}
@@ -230,12 +925,14 @@ func (c *gClient) do(ctx context.Context, u *url.URL) ([]byte, error) {
 	}
 
 	if resp.StatusCode == http.StatusTooManyRequests {
+		c.setRateLimited()
+
 		cookie := strings.Split(resp.Header.Get(headerKeySetCookie), ";")
 		if len(cookie) > 0 {
-			c.cookie = cookie[0]
-			r.Header.Set(headerKeyCookie, cookie[0])
+			c.setCookie(cookie[0])
+			r.Header.Set(headerKeyCookie, c.cookieHeader())
 
-			resp, err = c.httpClient.Do(r)
+			resp, err = c.send(r)
 			if err != nil {
 				return nil, err
 			}
@@ -247,7 +944,11 @@ func (c *gClient) do(ctx context.Context, u *url.URL) ([]byte, error) {
 		return nil, fmt.Errorf("%w: "+errReqDataF, ErrRequestFailed, resp.StatusCode, resp.Status)
 	}
 
-	return io.ReadAll(resp.Body)
+	if isConsentPage(resp) {
+		return nil, ErrConsentRequired
+	}
+
+	return c.read(r.URL, resp)
 }
 
 // doPost performs an HTTP POST request to the specified URL with the given payload.
@@ -262,16 +963,27 @@ func (c *gClient) do(ctx context.Context, u *url.URL) ([]byte, error) {
 //
 // Returns the response body as bytes or an error if the request fails.
 func (c *gClient) doPost(ctx context.Context, u *url.URL, payload string) ([]byte, error) {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
+
 	r, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), strings.NewReader(payload))
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", errCreateRequest, err)
 	}
 
 	r.Header.Add(headerKeyContentType, contentTypeForm)
-	r.Header.Add(headerKeyUserAgent, defaultUserAgent)
+	r.Header.Add(headerKeyUserAgent, c.userAgent)
+
+	if len(c.clientData) != 0 {
+		r.Header.Add(headerKeyClientData, c.clientData)
+	}
+
+	for k, v := range c.headers {
+		r.Header[k] = append([]string(nil), v...)
+	}
 
-	if len(c.cookie) != 0 {
-		r.Header.Add(headerKeyCookie, c.cookie)
+	if cookie := c.cookieHeader(); len(cookie) != 0 {
+		r.Header.Add(headerKeyCookie, cookie)
 	}
 
 	if c.debug {
@@ -279,7 +991,7 @@ func (c *gClient) doPost(ctx context.Context, u *url.URL, payload string) ([]byt
 		log.Println("[Debug] POST Request payload: ", payload)
 	}
 
-	resp, err := c.httpClient.Do(r)
+	resp, err := c.send(r)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", errDoRequest, err)
 	}
@@ -290,12 +1002,14 @@ func (c *gClient) doPost(ctx context.Context, u *url.URL, payload string) ([]byt
 	}
 
 	if resp.StatusCode == http.StatusTooManyRequests {
+		c.setRateLimited()
+
 		cookie := strings.Split(resp.Header.Get(headerKeySetCookie), ";")
 		if len(cookie) > 0 {
-			c.cookie = cookie[0]
-			r.Header.Set(headerKeyCookie, cookie[0])
+			c.setCookie(cookie[0])
+			r.Header.Set(headerKeyCookie, c.cookieHeader())
 
-			resp, err = c.httpClient.Do(r)
+			resp, err = c.send(r)
 			if err != nil {
 				return nil, err
 			}
@@ -307,33 +1021,137 @@ func (c *gClient) doPost(ctx context.Context, u *url.URL, payload string) ([]byt
 		return nil, fmt.Errorf("%w: "+errReqDataF, ErrRequestFailed, resp.StatusCode, resp.Status)
 	}
 
-	return io.ReadAll(resp.Body)
+	if isConsentPage(resp) {
+		return nil, ErrConsentRequired
+	}
+
+	return c.read(r.URL, resp)
+}
+
+// requestContext bounds ctx with the WithTimeout duration, if any.
+func (c *gClient) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+// send applies the request modifier, if any, and performs the request with the HTTP client.
+// With a response buffer the body is read and recorded, then served again from memory.
+func (c *gClient) send(r *http.Request) (*http.Response, error) {
+	if c.requestModifier != nil {
+		c.requestModifier(r)
+	}
+
+	resp, err := c.httpClient.Do(r)
+	if err != nil || c.responses == nil {
+		return resp, err
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	c.responses.add(RawResponse{Endpoint: r.URL.Path, StatusCode: resp.StatusCode, Body: b})
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+
+	return resp, nil
+}
+
+// setRateLimited records that an HTTP 429 response was just received.
+func (c *gClient) setRateLimited() {
+	c.rm.Lock()
+	defer c.rm.Unlock()
+
+	c.lastRateLimit = time.Now()
+}
+
+// getLastRateLimit returns the time the last HTTP 429 response was received,
+// or the zero time if none was.
+func (c *gClient) getLastRateLimit() time.Time {
+	c.rm.RLock()
+	defer c.rm.RUnlock()
+
+	return c.lastRateLimit
+}
+
+// getCookie returns the cookie received from rate-limited responses in a thread-safe manner.
+func (c *gClient) getCookie() string {
+	c.km.RLock()
+	defer c.km.RUnlock()
+	return c.cookie
+}
+
+// setCookie stores the cookie received from a rate-limited response in a thread-safe manner.
+func (c *gClient) setCookie(cookie string) {
+	c.km.Lock()
+	defer c.km.Unlock()
+	c.cookie = cookie
+}
+
+// cookieHeader returns the Cookie header value combining the consent cookie
+// and the cookie received from rate-limited responses.
+func (c *gClient) cookieHeader() string {
+	cookie := c.getCookie()
+	switch {
+	case len(c.consentCookie) == 0:
+		return cookie
+	case len(cookie) == 0:
+		return c.consentCookie
+	}
+
+	return c.consentCookie + "; " + cookie
+}
+
+// isConsentPage reports whether the response is the HTML consent page Google
+// redirects to instead of serving data. The APIs never answer with HTML otherwise.
+func isConsentPage(resp *http.Response) bool {
+	return strings.HasPrefix(resp.Header.Get(headerKeyContentType), contentTypeHTML)
+}
+
+// read reads the response body and checks it with the response validator, if any.
+func (c *gClient) read(u *url.URL, resp *http.Response) ([]byte, error) {
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.responseValidator != nil {
+		if err := c.responseValidator(u.Path, b); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrRequestFailed, err)
+		}
+	}
+
+	return b, nil
 }
 
 // unmarshal parses JSON string data into the destination struct.
-// It wraps any JSON parsing errors with additional context.
+// It wraps any JSON parsing errors in a ParseError carrying the start of the data.
 func (c *gClient) unmarshal(str string, dest interface{}) error {
 	if err := json.Unmarshal([]byte(str), dest); err != nil {
-		return fmt.Errorf("%s: %w", errParsing, err)
+		return newParseError(str, err)
 	}
 
 	return nil
 }
 
-// extractJSONFromResponse extracts trending search terms from the batch execute API response.
+// extractJSONFromResponse extracts trending searches from the batch execute API response.
 // The response format is a complex nested structure where the actual data is embedded
 // as a JSON string within a JSON array.
 //
 // The method parses through each line of the response looking for JSON arrays,
-// then extracts the trending topic strings from the nested structure.
+// then extracts the trending items from the nested structure (see parseTrendItem).
 //
-// Returns a slice of trending search term strings or an error if parsing fails.
-func (c *gClient) extractJSONFromResponse(text string) ([]string, error) {
+// Returns a slice of trending searches or an error if parsing fails.
+func (c *gClient) extractJSONFromResponse(text string) ([]*TrendingSearch, error) {
 	if c.debug {
 		log.Println("[Debug] Extracting JSON from API response")
 	}
 
-	var result []string
+	var result []*TrendingSearch
 
 	for _, line := range strings.Split(text, "\n") {
 		trimmed := strings.TrimSpace(line)
@@ -367,9 +1185,9 @@ func (c *gClient) extractJSONFromResponse(text string) ([]string, error) {
 						}
 
 						for _, item := range items {
-							if itemArr, ok := item.([]interface{}); ok && len(itemArr) > 0 {
-								if topic, ok := itemArr[0].(string); ok {
-									result = append(result, topic)
+							if itemArr, ok := item.([]interface{}); ok {
+								if search := parseTrendItem(itemArr, c.maxArticles); search != nil {
+									result = append(result, search)
 								}
 							}
 						}
@@ -397,20 +1215,131 @@ func (c *gClient) extractJSONFromResponse(text string) ([]string, error) {
 	return nil, errors.New("no valid JSON found in response")
 }
 
+// parseTrendItem converts a single batch execute trend item into a TrendingSearch.
+// The item is a positional array: index 0 holds the query and index 1 the list of news
+// articles (see parseTrendArticle). Returns nil if the item has no query.
+//
+// At most maxArticles articles are kept when maxArticles is positive. The trend
+// Image is taken from the first kept article that has one and is left nil when
+// no such article carries an image.
+func parseTrendItem(item []interface{}, maxArticles int) *TrendingSearch {
+	if len(item) == 0 {
+		return nil
+	}
+
+	query, ok := item[0].(string)
+	if !ok {
+		return nil
+	}
+
+	search := &TrendingSearch{
+		Title:    &SearchTitle{Query: query},
+		Articles: []*SearchArticle{},
+	}
+
+	if len(item) > 1 {
+		articles, _ := item[1].([]interface{})
+		if maxArticles > 0 && len(articles) > maxArticles {
+			search.Articles = make([]*SearchArticle, 0, maxArticles)
+		} else {
+			search.Articles = make([]*SearchArticle, 0, len(articles))
+		}
+
+		for _, v := range articles {
+			if maxArticles > 0 && len(search.Articles) == maxArticles {
+				break
+			}
+
+			arr, ok := v.([]interface{})
+			if !ok {
+				continue
+			}
+
+			article := parseTrendArticle(arr)
+			if article == nil {
+				continue
+			}
+
+			if search.Image == nil && article.Image != nil {
+				search.Image = article.Image
+			}
+			search.Articles = append(search.Articles, article)
+		}
+	}
+
+	return search
+}
+
+// parseTrendArticle converts a batch execute news article into a SearchArticle.
+// The article is a positional array of title, URL, source, publish time and image URL.
+// Image is nil when the article has no image URL. Returns nil if the article has no title or URL.
+func parseTrendArticle(arr []interface{}) *SearchArticle {
+	str := func(i int) string {
+		if i < len(arr) {
+			if v, ok := arr[i].(string); ok {
+				return v
+			}
+		}
+		return ""
+	}
+
+	article := &SearchArticle{
+		Title:  str(0),
+		URL:    str(1),
+		Source: str(2),
+	}
+	if len(article.Title) == 0 && len(article.URL) == 0 {
+		return nil
+	}
+
+	if imageURL := str(4); len(imageURL) != 0 {
+		article.Image = &SearchImage{
+			NewsURL:  article.URL,
+			Source:   article.Source,
+			ImageURL: imageURL,
+		}
+	}
+
+	return article
+}
+
+// trendsNewURL builds the batch execute URL, localized to hl when it is not empty.
+func trendsNewURL(hl string) *url.URL {
+	u, _ := url.Parse(gBatchExecute)
+	if len(hl) != 0 {
+		p := make(url.Values)
+		p.Set(paramHl, hl)
+		u.RawQuery = p.Encode()
+	}
+
+	return u
+}
+
+// trendsNewPayload builds the batch execute form payload calling rpcID for the trends
+// of loc in the category cat, 0 being all categories. The RPC arguments are a JSON
+// array embedded as a string in the outer RPC array, so both levels are marshaled
+// rather than formatted to escape loc correctly.
+func trendsNewPayload(rpcID, loc string, cat int) string {
+	// marshaling nil, strings and ints cannot fail
+	args, _ := json.Marshal([]interface{}{nil, nil, NormalizeGeo(loc), cat, nil, trendingNowHours})
+	req, _ := json.Marshal([][][]string{{{rpcID, string(args)}}})
+
+	return url.Values{paramFReq: {string(req)}}.Encode()
+}
+
 // trendsNew fetches trending searches using the new Google Trends batch execute API.
-// This method is used by DailyNew and DailyTrendingSearchNew functions.
+// This method is used by DailyNew, DailyTrendingSearchNew and DailyByCategories functions.
 //
 // Parameters:
 //   - ctx: Context for request cancellation and timeouts
-//   - hl: Host language code (e.g., "EN", "RU") - currently unused but kept for API consistency
+//   - hl: Host language code (e.g., "EN", "RU"), sent as the hl query param; empty omits it
 //   - loc: Location code for regional trends (e.g., "US", "GB", "RU")
+//   - cat: Trending category id, 0 for all categories
 //
-// Returns a slice of trending search term strings or an error if the request fails.
-func (c *gClient) trendsNew(ctx context.Context, hl, loc string) ([]string, error) {
-	u, _ := url.Parse(gBatchExecute)
-
-	// Create payload for the new API
-	payload := fmt.Sprintf("f.req=[[[i0OFE,\"[null, null, \\\"%s\\\", 0, null, 48]\"]]]", loc)
+// Returns a slice of trending searches or an error if the request fails.
+func (c *gClient) trendsNew(ctx context.Context, hl, loc string, cat int) ([]*TrendingSearch, error) {
+	u := trendsNewURL(hl)
+	payload := trendsNewPayload(c.batchRPCID, loc, cat)
 
 	if c.debug {
 		log.Println("[Debug] Using new Google Trends API with payload:", payload)
@@ -423,3 +1352,39 @@ func (c *gClient) trendsNew(ctx context.Context, hl, loc string) ([]string, erro
 
 	return c.extractJSONFromResponse(string(data))
 }
+
+// dailyLegacy fetches trending searches grouped by day from the legacy daily trends API.
+// Unlike trendsNew it reports FormattedTraffic, images and article snippets, but the
+// endpoint is less reliable. The most recent day comes first. A non-empty endDate in
+// the "20060102" format makes the result end at that day instead of today.
+func (c *gClient) dailyLegacy(ctx context.Context, hl, loc, endDate string) ([]*TrendingSearchDays, error) {
+	u, _ := url.Parse(gAPI + gSDaily)
+
+	p := c.defaultParams()
+	p.Set(paramHl, hl)
+	p.Set(paramGeo, NormalizeGeo(loc))
+	p.Set(paramNS, "15")
+	if len(endDate) != 0 {
+		p.Set(paramEndDate, endDate)
+	}
+	u.RawQuery = p.Encode()
+
+	b, err := c.do(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	// google api returns not valid json :(
+	str := strings.Replace(string(b), ")]}',", "", 1)
+
+	out := new(dailyOut)
+	if err := c.unmarshal(str, out); err != nil {
+		return nil, err
+	}
+
+	if out.Default == nil {
+		return []*TrendingSearchDays{}, nil
+	}
+
+	return out.Default.Days, nil
+}
@@ -3,11 +3,19 @@ package googletrends
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -22,6 +30,10 @@ func (m *mockHTTPClient) Do(req *http.Request) (*http.Response, error) {
 	return m.doFunc(req)
 }
 
+// testToken is a widget token long enough to pass ExploreWidget.HasValidToken.
+// Tests routing by token append their key to it.
+const testToken = "APP6_UEAAAAAZQ8xYz0-"
+
 // newMockResponse creates a mock HTTP response with the given status code and body.
 func newMockResponse(statusCode int, body string) *http.Response {
 	return &http.Response{
@@ -31,6 +43,18 @@ func newMockResponse(statusCode int, body string) *http.Response {
 	}
 }
 
+// useMockClient replaces the package-level client with one backed by doFunc
+// for the duration of the test. Tests using it must not call t.Parallel.
+func useMockClient(t testing.TB, doFunc func(req *http.Request) (*http.Response, error)) *gClient {
+	t.Helper()
+
+	c := newGClient(WithHTTPClient(&mockHTTPClient{doFunc: doFunc}))
+	orig := setDefaultClient(c)
+	t.Cleanup(func() { setDefaultClient(orig) })
+
+	return c
+}
+
 func TestNewGClient(t *testing.T) {
 	t.Parallel()
 
@@ -63,6 +87,82 @@ func TestGClientDefaultParams(t *testing.T) {
 	assert.Equal(t, "EN", params.Get(paramHl))
 }
 
+func TestWithDefaultParam(t *testing.T) {
+	t.Parallel()
+
+	var query url.Values
+	mockClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			query = req.URL.Query()
+			return newMockResponse(http.StatusOK, `)]}',{"default":{"trendingSearchesDays":[]}}`), nil
+		},
+	}
+
+	c := newGClient(
+		WithHTTPClient(mockClient),
+		WithDefaultParam(paramTZ, "-120"),
+		WithDefaultParam(paramGeo, "DE"),
+		WithDefaultParam("custom", "1"),
+		WithDefaultParam(paramToken, "forged"),
+		WithDefaultParam(paramReq, "{}"),
+	)
+
+	params := c.defaultParams()
+	assert.Equal(t, "-120", params.Get(paramTZ))
+	assert.Equal(t, "1", params.Get("custom"))
+	assert.Equal(t, "EN", params.Get(paramHl))
+	assert.NotContains(t, params, paramToken)
+	assert.NotContains(t, params, paramReq)
+
+	_, err := c.dailyLegacy(context.Background(), "FR", "US", "")
+	require.NoError(t, err)
+	assert.Equal(t, "-120", query.Get(paramTZ))
+	assert.Equal(t, "1", query.Get("custom"))
+	assert.Equal(t, "FR", query.Get(paramHl))
+	assert.Equal(t, "US", query.Get(paramGeo))
+}
+
+func TestWithLegacyParams(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		params   LegacyParams
+		expected map[string]string
+	}{
+		{
+			name:   "all fields",
+			params: LegacyParams{FirstItem: 1, FirstSort: 2, ResultsIndex: 3, ResultsSize: 4},
+			expected: map[string]string{
+				"fi": "1",
+				"fs": "2",
+				"ri": "3",
+				"rs": "4",
+			},
+		},
+		{
+			name:   "zero fields keep defaults",
+			params: LegacyParams{ResultsSize: 50},
+			expected: map[string]string{
+				"fi": "0",
+				"fs": "0",
+				"ri": "300",
+				"rs": "50",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := newGClient(WithLegacyParams(tt.params)).defaultParams()
+
+			for key, value := range tt.expected {
+				assert.Equal(t, value, params.Get(key), key)
+			}
+		})
+	}
+}
+
 func TestGClientDo(t *testing.T) {
 	t.Parallel()
 
@@ -191,6 +291,26 @@ func TestGClientUnmarshal(t *testing.T) {
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), errParsing)
+
+		var parseErr *ParseError
+		require.True(t, errors.As(err, &parseErr))
+		assert.Equal(t, "invalid json", parseErr.Snippet)
+
+		var syntaxErr *json.SyntaxError
+		assert.True(t, errors.As(err, &syntaxErr))
+	})
+
+	t.Run("long data is truncated on rune boundary", func(t *testing.T) {
+		// 199 ASCII bytes followed by 2-byte runes: byte 200 falls inside a rune
+		data := strings.Repeat("<", parseSnippetLen-1) + strings.Repeat("ж", 10)
+
+		var result map[string]interface{}
+		err := c.unmarshal(data, &result)
+
+		var parseErr *ParseError
+		require.True(t, errors.As(err, &parseErr))
+		assert.Len(t, parseErr.Snippet, parseSnippetLen-1)
+		assert.True(t, utf8.ValidString(parseErr.Snippet))
 	})
 }
 
@@ -230,6 +350,211 @@ func TestGClientLocationsCache(t *testing.T) {
 	})
 }
 
+func TestWithMinTLSVersion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("configures default transport", func(t *testing.T) {
+		c := newGClient(WithMinTLSVersion(tls.VersionTLS12))
+
+		hc, ok := c.httpClient.(*http.Client)
+		require.True(t, ok)
+		transport, ok := hc.Transport.(*http.Transport)
+		require.True(t, ok)
+		require.NotNil(t, transport.TLSClientConfig)
+		assert.Equal(t, uint16(tls.VersionTLS12), transport.TLSClientConfig.MinVersion)
+
+		// the shared default transport is left untouched
+		assert.NotSame(t, http.DefaultTransport, transport)
+	})
+
+	t.Run("no-op with custom client", func(t *testing.T) {
+		mock := &mockHTTPClient{}
+		c := newGClient(WithHTTPClient(mock), WithMinTLSVersion(tls.VersionTLS12))
+		assert.Equal(t, mock, c.httpClient)
+
+		c = newGClient(WithMinTLSVersion(tls.VersionTLS12), WithHTTPClient(mock))
+		assert.Equal(t, mock, c.httpClient)
+	})
+
+	t.Run("no-op with round tripper chain", func(t *testing.T) {
+		c := newGClient(WithMinTLSVersion(tls.VersionTLS12), WithRoundTripperChain())
+
+		hc, ok := c.httpClient.(*http.Client)
+		require.True(t, ok)
+		assert.Equal(t, http.DefaultTransport, hc.Transport)
+	})
+}
+
+func TestWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sets default client timeout", func(t *testing.T) {
+		c := newGClient(WithTimeout(5 * time.Second))
+
+		hc, ok := c.httpClient.(*http.Client)
+		require.True(t, ok)
+		assert.NotSame(t, http.DefaultClient, hc)
+		assert.Equal(t, 5*time.Second, hc.Timeout)
+		assert.Zero(t, http.DefaultClient.Timeout)
+	})
+
+	t.Run("bounds request context of custom client", func(t *testing.T) {
+		var deadlines []bool
+		mock := &mockHTTPClient{
+			doFunc: func(req *http.Request) (*http.Response, error) {
+				_, ok := req.Context().Deadline()
+				deadlines = append(deadlines, ok)
+				return newMockResponse(http.StatusOK, "{}"), nil
+			},
+		}
+		c := newGClient(WithHTTPClient(mock), WithTimeout(5*time.Second))
+		u, _ := url.Parse("https://example.com/test")
+
+		_, err := c.do(context.Background(), u)
+		require.NoError(t, err)
+		_, err = c.doPost(context.Background(), u, "payload=test")
+		require.NoError(t, err)
+
+		assert.Equal(t, mock, c.httpClient)
+		assert.Equal(t, []bool{true, true}, deadlines)
+	})
+
+	t.Run("combines with min TLS version", func(t *testing.T) {
+		for name, opts := range map[string][]Option{
+			"timeout first":     {WithTimeout(5 * time.Second), WithMinTLSVersion(tls.VersionTLS12)},
+			"TLS version first": {WithMinTLSVersion(tls.VersionTLS12), WithTimeout(5 * time.Second)},
+		} {
+			t.Run(name, func(t *testing.T) {
+				c := newGClient(opts...)
+
+				hc, ok := c.httpClient.(*http.Client)
+				require.True(t, ok)
+				assert.Equal(t, 5*time.Second, hc.Timeout)
+				transport, ok := hc.Transport.(*http.Transport)
+				require.True(t, ok)
+				require.NotNil(t, transport.TLSClientConfig)
+				assert.Equal(t, uint16(tls.VersionTLS12), transport.TLSClientConfig.MinVersion)
+			})
+		}
+	})
+
+	t.Run("non-positive duration disables timeout", func(t *testing.T) {
+		c := newGClient(WithTimeout(-time.Second))
+
+		assert.Equal(t, http.DefaultClient, c.httpClient)
+		assert.Zero(t, c.timeout)
+	})
+}
+
+func TestWithoutCache(t *testing.T) {
+	var calls int
+	c := useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		calls++
+		if strings.HasSuffix(req.URL.Path, gSCategories) {
+			return newMockResponse(http.StatusOK, `)]}'{"name":"All categories","id":0}`), nil
+		}
+		return newMockResponse(http.StatusOK, `)]}'{"name":"Worldwide","id":"","children":[{"name":"United States","id":"US"}]}`), nil
+	})
+	WithoutCache()(c)
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		_, err := ExploreCategories(ctx)
+		require.NoError(t, err)
+		_, err = ExploreLocations(ctx)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 4, calls)
+	assert.Nil(t, c.getCategories())
+	assert.Nil(t, c.getLocations())
+}
+
+func TestWithResponseBuffer(t *testing.T) {
+	c := useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		word := strings.TrimPrefix(req.URL.Path, "/trends/api"+gSAutocomplete+"/")
+		if word == "broken" {
+			return newMockResponse(http.StatusInternalServerError, "oops"), nil
+		}
+		return newMockResponse(http.StatusOK, fmt.Sprintf(`)]}',{"default":{"topics":[{"title":%q}]}}`, word)), nil
+	})
+
+	ctx := context.Background()
+	assert.Empty(t, RecentResponses())
+
+	WithResponseBuffer(2)(c)
+
+	for _, word := range []string{"golang", "rust"} {
+		topics, err := Search(ctx, word, langEN)
+		require.NoError(t, err)
+		require.Len(t, topics, 1)
+		assert.Equal(t, word, topics[0].Title)
+	}
+
+	_, err := Search(ctx, "broken", langEN)
+	assert.ErrorIs(t, err, ErrRequestFailed)
+
+	recent := RecentResponses()
+	require.Len(t, recent, 2)
+	assert.Equal(t, "/trends/api/autocomplete/rust", recent[0].Endpoint)
+	assert.Equal(t, http.StatusOK, recent[0].StatusCode)
+	assert.Contains(t, string(recent[0].Body), `"title":"rust"`)
+	assert.Equal(t, http.StatusInternalServerError, recent[1].StatusCode)
+	assert.Equal(t, "oops", string(recent[1].Body))
+
+	t.Run("concurrent", func(t *testing.T) {
+		WithResponseBuffer(3)(c)
+
+		wg := new(sync.WaitGroup)
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = Search(ctx, "golang", langEN)
+				_ = RecentResponses()
+			}()
+		}
+		wg.Wait()
+
+		assert.Len(t, RecentResponses(), 3)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		WithResponseBuffer(0)(c)
+		assert.Empty(t, RecentResponses())
+	})
+}
+
+func TestRateLimited(t *testing.T) {
+	var limited bool
+	c := useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		if limited {
+			return newMockResponse(http.StatusTooManyRequests, ""), nil
+		}
+		return newMockResponse(http.StatusOK, `)]}',{"default":{"topics":[]}}`), nil
+	})
+
+	ctx := context.Background()
+	_, err := Search(ctx, "golang", langEN)
+	require.NoError(t, err)
+	assert.False(t, RateLimited())
+	assert.True(t, LastRateLimit().IsZero())
+
+	limited = true
+	before := time.Now()
+	_, err = Search(ctx, "golang", langEN)
+	assert.ErrorIs(t, err, ErrRequestFailed)
+	assert.True(t, RateLimited())
+	assert.False(t, LastRateLimit().Before(before))
+
+	// a limit outside the window is no longer recent
+	c.rm.Lock()
+	c.lastRateLimit = time.Now().Add(-2 * rateLimitWindow)
+	c.rm.Unlock()
+	assert.False(t, RateLimited())
+	assert.False(t, LastRateLimit().IsZero())
+}
+
 func TestGClientTooManyRequests(t *testing.T) {
 	t.Parallel()
 
@@ -254,7 +579,51 @@ func TestGClientTooManyRequests(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, `{"status": "ok"}`, string(result))
 	assert.Equal(t, 2, callCount)
-	assert.Equal(t, "test_cookie=value", c.cookie)
+	assert.Equal(t, "test_cookie=value", c.getCookie())
+}
+
+func TestGClientTooManyRequestsConcurrent(t *testing.T) {
+	t.Parallel()
+
+	// every request is rate limited until it carries the cookie
+	mockClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			if len(req.Header.Get(headerKeyCookie)) == 0 {
+				resp := newMockResponse(http.StatusTooManyRequests, "")
+				resp.Header.Set(headerKeySetCookie, "NID=limited; Path=/")
+				return resp, nil
+			}
+			return newMockResponse(http.StatusOK, `{"status": "ok"}`), nil
+		},
+	}
+
+	c := newGClient(WithHTTPClient(mockClient))
+	u, _ := url.Parse("https://example.com/test")
+
+	const n = 16
+	errs := make([]error, n)
+	start := make(chan struct{})
+	wg := new(sync.WaitGroup)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+
+			if i%2 == 0 {
+				_, errs[i] = c.do(context.Background(), u)
+			} else {
+				_, errs[i] = c.doPost(context.Background(), u, "")
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, "NID=limited", c.getCookie())
 }
 
 func TestExtractJSONFromResponse(t *testing.T) {
@@ -289,3 +658,627 @@ func TestDebugMode(t *testing.T) {
 	c.debug = false
 	assert.False(t, c.debug)
 }
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestWithRoundTripperChain(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+	trace := func(name string) func(http.RoundTripper) http.RoundTripper {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" in")
+				resp, err := next.RoundTrip(r)
+				calls = append(calls, name+" out")
+				return resp, err
+			})
+		}
+	}
+	// stub answers without calling the base transport
+	stub := func(next http.RoundTripper) http.RoundTripper {
+		assert.Equal(t, http.DefaultTransport, next)
+		return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			calls = append(calls, "stub")
+			return newMockResponse(http.StatusOK, `{"status": "ok"}`), nil
+		})
+	}
+
+	c := newGClient(WithRoundTripperChain(trace("auth"), trace("tracing"), stub))
+	u, _ := url.Parse("https://example.com/test")
+
+	result, err := c.do(context.Background(), u)
+
+	require.NoError(t, err)
+	assert.Equal(t, `{"status": "ok"}`, string(result))
+	assert.Equal(t, []string{"auth in", "tracing in", "stub", "tracing out", "auth out"}, calls)
+}
+
+func TestWithClientData(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected string
+	}{
+		{
+			name:     "header omitted when unset",
+			opts:     nil,
+			expected: "",
+		},
+		{
+			name:     "header attached when set",
+			opts:     []Option{WithClientData("CIa2yQE=")},
+			expected: "CIa2yQE=",
+		},
+		{
+			name:     "later option overrides earlier",
+			opts:     []Option{WithClientData("CIa2yQE="), WithClientData("CJK3yQE=")},
+			expected: "CJK3yQE=",
+		},
+		{
+			name:     "empty value removes header",
+			opts:     []Option{WithClientData("CIa2yQE="), WithClientData("")},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var headers []http.Header
+			mockClient := &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					headers = append(headers, req.Header.Clone())
+					return newMockResponse(http.StatusOK, "{}"), nil
+				},
+			}
+
+			c := newGClient(append([]Option{WithHTTPClient(mockClient)}, tt.opts...)...)
+			u, _ := url.Parse("https://example.com/test")
+
+			_, err := c.do(context.Background(), u)
+			require.NoError(t, err)
+			_, err = c.doPost(context.Background(), u, "payload=test")
+			require.NoError(t, err)
+
+			require.Len(t, headers, 2)
+			for _, h := range headers {
+				_, ok := h[headerKeyClientData]
+				assert.Equal(t, tt.expected != "", ok)
+				assert.Equal(t, tt.expected, h.Get(headerKeyClientData))
+			}
+		})
+	}
+}
+
+func TestWithAccept(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected string
+	}{
+		{
+			name:     "defaults to JSON",
+			opts:     nil,
+			expected: contentTypeJSON,
+		},
+		{
+			name:     "override reaches transport",
+			opts:     []Option{WithAccept("*/*")},
+			expected: "*/*",
+		},
+		{
+			name:     "empty value keeps default",
+			opts:     []Option{WithAccept("")},
+			expected: contentTypeJSON,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var accept []string
+			mockClient := &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					accept = req.Header.Values(headerKeyAccept)
+					return newMockResponse(http.StatusOK, "{}"), nil
+				},
+			}
+
+			c := newGClient(append([]Option{WithHTTPClient(mockClient)}, tt.opts...)...)
+			u, _ := url.Parse("https://example.com/test")
+
+			_, err := c.do(context.Background(), u)
+			require.NoError(t, err)
+			assert.Equal(t, []string{tt.expected}, accept)
+		})
+	}
+}
+
+func TestWithUserAgent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected string
+	}{
+		{
+			name:     "defaults to browser user agent",
+			opts:     nil,
+			expected: defaultUserAgent,
+		},
+		{
+			name:     "override reaches transport",
+			opts:     []Option{WithUserAgent("MyBot/1.0")},
+			expected: "MyBot/1.0",
+		},
+		{
+			name:     "empty value keeps default",
+			opts:     []Option{WithUserAgent("")},
+			expected: defaultUserAgent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var agents [][]string
+			mockClient := &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					agents = append(agents, req.Header.Values(headerKeyUserAgent))
+					return newMockResponse(http.StatusOK, "{}"), nil
+				},
+			}
+
+			c := newGClient(append([]Option{WithHTTPClient(mockClient)}, tt.opts...)...)
+			u, _ := url.Parse("https://example.com/test")
+
+			_, err := c.do(context.Background(), u)
+			require.NoError(t, err)
+			_, err = c.doPost(context.Background(), u, "")
+			require.NoError(t, err)
+			assert.Equal(t, [][]string{{tt.expected}, {tt.expected}}, agents)
+		})
+	}
+}
+
+func TestWithBrowserHeaders(t *testing.T) {
+	t.Parallel()
+
+	send := func(t *testing.T, opts ...Option) []http.Header {
+		var headers []http.Header
+		mockClient := &mockHTTPClient{
+			doFunc: func(req *http.Request) (*http.Response, error) {
+				headers = append(headers, req.Header.Clone())
+				return newMockResponse(http.StatusOK, "{}"), nil
+			},
+		}
+
+		c := newGClient(append([]Option{WithHTTPClient(mockClient)}, opts...)...)
+		u, _ := url.Parse("https://example.com/test")
+
+		_, err := c.do(context.Background(), u)
+		require.NoError(t, err)
+		_, err = c.doPost(context.Background(), u, "payload=test")
+		require.NoError(t, err)
+		require.Len(t, headers, 2)
+
+		return headers
+	}
+
+	t.Run("headers omitted by default", func(t *testing.T) {
+		for _, h := range send(t) {
+			assert.Empty(t, h.Get("Sec-Fetch-Mode"))
+			assert.Empty(t, h.Get("Sec-Ch-Ua"))
+		}
+	})
+
+	t.Run("default set attached", func(t *testing.T) {
+		for _, h := range send(t, WithBrowserHeaders()) {
+			for k, v := range browserHeaders {
+				assert.Equal(t, []string{v}, h.Values(k), k)
+			}
+			assert.Equal(t, defaultUserAgent, h.Get(headerKeyUserAgent))
+		}
+	})
+
+	t.Run("overrides replace, add and remove headers", func(t *testing.T) {
+		headers := send(t, WithBrowserHeaders(http.Header{
+			"sec-ch-ua-platform": {`"Windows"`},
+			"Sec-Ch-Ua-Mobile":   nil,
+			"Sec-Fetch-User":     {"?1"},
+		}))
+
+		for _, h := range headers {
+			assert.Equal(t, `"Windows"`, h.Get("Sec-Ch-Ua-Platform"))
+			_, ok := h["Sec-Ch-Ua-Mobile"]
+			assert.False(t, ok)
+			assert.Equal(t, "?1", h.Get("Sec-Fetch-User"))
+			assert.Equal(t, "cors", h.Get("Sec-Fetch-Mode"))
+		}
+	})
+}
+
+func TestWithRequestModifier(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+	var seen []http.Header
+	first := true
+	mockClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			seen = append(seen, req.Header.Clone())
+			if first {
+				first = false
+				resp := newMockResponse(http.StatusTooManyRequests, "")
+				resp.Header.Set(headerKeySetCookie, "NID=1; Path=/")
+				return resp, nil
+			}
+			return newMockResponse(http.StatusOK, "{}"), nil
+		},
+	}
+
+	c := newGClient(
+		WithHTTPClient(mockClient),
+		WithRequestModifier(func(r *http.Request) {
+			calls++
+			r.Header.Set("X-Signature", strconv.Itoa(calls))
+			r.Header.Set(headerKeyUserAgent, "modified")
+		}),
+	)
+	u, _ := url.Parse("https://example.com/test")
+
+	_, err := c.do(context.Background(), u)
+	require.NoError(t, err)
+	_, err = c.doPost(context.Background(), u, "payload=test")
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, calls)
+	require.Len(t, seen, 3)
+	for i, h := range seen {
+		assert.Equal(t, strconv.Itoa(i+1), h.Get("X-Signature"))
+		assert.Equal(t, "modified", h.Get(headerKeyUserAgent))
+	}
+}
+
+func TestWithResponseValidator(t *testing.T) {
+	t.Parallel()
+
+	errDrift := errors.New("schema drift")
+
+	tests := []struct {
+		name        string
+		body        string
+		validator   func(endpoint string, body []byte) error
+		expectedErr error
+	}{
+		{
+			name: "no validator",
+			body: "<html>captcha</html>",
+		},
+		{
+			name:      "valid response",
+			body:      `)]}'{"widgets":[]}`,
+			validator: ValidateNotBlocked,
+		},
+		{
+			name:        "block page rejected",
+			body:        "\n  <!DOCTYPE html><html>unusual traffic</html>",
+			validator:   ValidateNotBlocked,
+			expectedErr: ErrBlocked,
+		},
+		{
+			name: "custom validator error",
+			body: "{}",
+			validator: func(endpoint string, body []byte) error {
+				return errDrift
+			},
+			expectedErr: errDrift,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var endpoints []string
+			opts := []Option{WithHTTPClient(&mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					return newMockResponse(http.StatusOK, tt.body), nil
+				},
+			})}
+			if tt.validator != nil {
+				opts = append(opts, WithResponseValidator(func(endpoint string, body []byte) error {
+					endpoints = append(endpoints, endpoint)
+					assert.Equal(t, tt.body, string(body))
+					return tt.validator(endpoint, body)
+				}))
+			}
+
+			c := newGClient(opts...)
+			u, _ := url.Parse("https://example.com/trends/api/explore")
+
+			for _, do := range []func() ([]byte, error){
+				func() ([]byte, error) { return c.do(context.Background(), u) },
+				func() ([]byte, error) { return c.doPost(context.Background(), u, "payload=test") },
+			} {
+				b, err := do()
+				if tt.expectedErr != nil {
+					assert.ErrorIs(t, err, tt.expectedErr)
+					assert.ErrorIs(t, err, ErrRequestFailed)
+					assert.Nil(t, b)
+					continue
+				}
+				require.NoError(t, err)
+				assert.Equal(t, tt.body, string(b))
+			}
+
+			if tt.validator != nil {
+				assert.Equal(t, []string{"/trends/api/explore", "/trends/api/explore"}, endpoints)
+			}
+		})
+	}
+}
+
+// consentPage is a trimmed copy of the page Google serves from consent.google.com.
+const consentPage = `<!DOCTYPE html><html lang="en"><head><title>Before you continue to Google</title></head>` +
+	`<body><form action="https://consent.google.com/save" method="POST">` +
+	`<input type="hidden" name="continue" value="https://trends.google.com/trends/api/explore">` +
+	`<button>Accept all</button></form></body></html>`
+
+func TestConsentRequired(t *testing.T) {
+	t.Parallel()
+
+	mockClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			resp := newMockResponse(http.StatusOK, consentPage)
+			resp.Header.Set(headerKeyContentType, "text/html; charset=UTF-8")
+			return resp, nil
+		},
+	}
+
+	c := newGClient(WithHTTPClient(mockClient))
+	u, _ := url.Parse("https://example.com/trends/api/explore")
+
+	_, err := c.do(context.Background(), u)
+	assert.ErrorIs(t, err, ErrConsentRequired)
+	_, err = c.doPost(context.Background(), u, "payload=test")
+	assert.ErrorIs(t, err, ErrConsentRequired)
+}
+
+func TestWithConsentCookie(t *testing.T) {
+	t.Parallel()
+
+	var cookies []string
+	first := true
+	mockClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			cookies = append(cookies, req.Header.Get(headerKeyCookie))
+			if first {
+				first = false
+				resp := newMockResponse(http.StatusTooManyRequests, "")
+				resp.Header.Set(headerKeySetCookie, "NID=1; Path=/")
+				return resp, nil
+			}
+			return newMockResponse(http.StatusOK, "{}"), nil
+		},
+	}
+
+	c := newGClient(WithHTTPClient(mockClient), WithConsentCookie("SOCS=abc"))
+	u, _ := url.Parse("https://example.com/test")
+
+	_, err := c.do(context.Background(), u)
+	require.NoError(t, err)
+	_, err = c.doPost(context.Background(), u, "payload=test")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"SOCS=abc", "SOCS=abc; NID=1", "SOCS=abc; NID=1"}, cookies)
+}
+
+// newBatchResponse builds a batch execute response body carrying the given trend items.
+func newBatchResponse(t testing.TB, items ...[]interface{}) string {
+	t.Helper()
+
+	data, err := json.Marshal([]interface{}{nil, items})
+	require.NoError(t, err)
+
+	envelope, err := json.Marshal([]interface{}{
+		[]interface{}{"wrb.fr", "i0OFE", string(data), nil, nil, nil, "generic"},
+		[]interface{}{"di", 50},
+		[]interface{}{"af.httprm", 49, "-1", 1},
+	})
+	require.NoError(t, err)
+
+	return ")]}'\n\n" + strconv.Itoa(len(envelope)) + "\n" + string(envelope) + "\n"
+}
+
+func TestTrendsNewNormalizesLocation(t *testing.T) {
+	t.Parallel()
+
+	var payload string
+	mockClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			b, _ := io.ReadAll(req.Body)
+			payload = string(b)
+			return newMockResponse(http.StatusOK, newBatchResponse(t, []interface{}{"golang"})), nil
+		},
+	}
+
+	c := newGClient(WithHTTPClient(mockClient))
+	searches, err := c.trendsNew(context.Background(), "EN", "usa", 0)
+
+	require.NoError(t, err)
+	require.Len(t, searches, 1)
+	assert.Equal(t, "golang", searches[0].Title.Query)
+	_, args := decodeTrendsPayload(t, payload)
+	assert.Equal(t, "US", args[2])
+}
+
+// decodeTrendsPayload decodes the RPC ID and arguments of a batch execute form payload.
+func decodeTrendsPayload(t *testing.T, payload string) (string, []interface{}) {
+	t.Helper()
+
+	form, err := url.ParseQuery(payload)
+	require.NoError(t, err)
+
+	var req [][][]string
+	require.NoError(t, json.Unmarshal([]byte(form.Get(paramFReq)), &req))
+	require.Len(t, req, 1)
+	require.Len(t, req[0], 1)
+	require.Len(t, req[0][0], 2)
+
+	var args []interface{}
+	require.NoError(t, json.Unmarshal([]byte(req[0][0][1]), &args))
+	require.Len(t, args, 6)
+
+	return req[0][0][0], args
+}
+
+func TestTrendsNewPayload(t *testing.T) {
+	t.Parallel()
+
+	for _, loc := range []string{"US", `U"S`, `US\`, "US&geo=GB", `"]]]`} {
+		t.Run(loc, func(t *testing.T) {
+			id, args := decodeTrendsPayload(t, trendsNewPayload(rpcTrendingNow, loc, 0))
+
+			assert.Equal(t, rpcTrendingNow, id)
+			assert.Equal(t, []interface{}{nil, nil, NormalizeGeo(loc), float64(0), nil, float64(trendingNowHours)}, args)
+		})
+	}
+
+	t.Run("category", func(t *testing.T) {
+		_, args := decodeTrendsPayload(t, trendsNewPayload(rpcTrendingNow, "US", 18))
+
+		assert.Equal(t, float64(18), args[3])
+	})
+}
+
+func TestWithBatchRPCID(t *testing.T) {
+	t.Parallel()
+
+	var payload string
+	mockClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			b, _ := io.ReadAll(req.Body)
+			payload = string(b)
+			return newMockResponse(http.StatusOK, newBatchResponse(t, []interface{}{"golang"})), nil
+		},
+	}
+
+	tests := []struct {
+		name     string
+		id       string
+		expected string
+	}{
+		{name: "custom id", id: "x7RPc", expected: "x7RPc"},
+		{name: "empty id keeps default", id: "", expected: rpcTrendingNow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newGClient(WithHTTPClient(mockClient), WithBatchRPCID(tt.id))
+			_, err := c.trendsNew(context.Background(), "EN", "US", 0)
+			require.NoError(t, err)
+
+			id, _ := decodeTrendsPayload(t, payload)
+			assert.Equal(t, tt.expected, id)
+		})
+	}
+}
+
+func TestTrendsNewImages(t *testing.T) {
+	t.Parallel()
+
+	body := newBatchResponse(t,
+		[]interface{}{"golang", []interface{}{
+			[]interface{}{"Go 1.23 released", "https://go.dev/blog", "Go Blog", []interface{}{1700000000}},
+			[]interface{}{"Go news", "https://news.example/go", "Example News", []interface{}{1700000000}, "https://img.example/go.jpg"},
+		}},
+		[]interface{}{"rust", []interface{}{
+			[]interface{}{"Rust 2024", "https://blog.rust-lang.org", "Rust Blog", []interface{}{1700000000}},
+		}},
+		[]interface{}{"zig"},
+	)
+
+	mockClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			return newMockResponse(http.StatusOK, body), nil
+		},
+	}
+
+	c := newGClient(WithHTTPClient(mockClient))
+	searches, err := c.trendsNew(context.Background(), "EN", "US", 0)
+	require.NoError(t, err)
+	require.Len(t, searches, 3)
+
+	golang := searches[0]
+	assert.True(t, golang.HasImage())
+	assert.Equal(t, &SearchImage{
+		NewsURL:  "https://news.example/go",
+		Source:   "Example News",
+		ImageURL: "https://img.example/go.jpg",
+	}, golang.Image)
+	require.Len(t, golang.Articles, 2)
+	assert.Nil(t, golang.Articles[0].Image)
+	assert.Equal(t, "Go 1.23 released", golang.Articles[0].Title)
+	assert.Equal(t, golang.Image, golang.Articles[1].Image)
+
+	rust := searches[1]
+	assert.False(t, rust.HasImage())
+	assert.Nil(t, rust.Image)
+	assert.Len(t, rust.Articles, 1)
+
+	zig := searches[2]
+	assert.False(t, zig.HasImage())
+	assert.Empty(t, zig.Articles)
+}
+
+func TestWithMaxArticles(t *testing.T) {
+	t.Parallel()
+
+	articles := make([]interface{}, 0, 30)
+	for i := 0; i < 30; i++ {
+		articles = append(articles, []interface{}{
+			fmt.Sprintf("Article %d", i), fmt.Sprintf("https://news.example/%d", i), "Example News",
+		})
+	}
+	body := newBatchResponse(t,
+		[]interface{}{"golang", articles},
+		[]interface{}{"rust", articles[:2]},
+	)
+
+	tests := []struct {
+		name     string
+		max      int
+		expected []int
+	}{
+		{name: "unlimited by default", max: 0, expected: []int{30, 2}},
+		{name: "small cap", max: 3, expected: []int{3, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					return newMockResponse(http.StatusOK, body), nil
+				},
+			}
+
+			c := newGClient(WithHTTPClient(mockClient), WithMaxArticles(tt.max))
+			searches, err := c.trendsNew(context.Background(), "EN", "US", 0)
+			require.NoError(t, err)
+			require.Len(t, searches, len(tt.expected))
+
+			for i, n := range tt.expected {
+				assert.Len(t, searches[i].Articles, n)
+				assert.Equal(t, "Article 0", searches[i].Articles[0].Title)
+			}
+			assert.Equal(t, tt.expected[0], cap(searches[0].Articles))
+		})
+	}
+}
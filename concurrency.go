@@ -0,0 +1,45 @@
+package googletrends
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// maxConcurrentRequests bounds the number of requests the fan-out helpers
+// run in parallel, to stay below Google rate limits.
+const maxConcurrentRequests = 4
+
+// forEachConcurrent calls fn for every index in [0, n), running at most limit
+// calls at the same time. It waits for all started calls and returns the joined
+// errors of the failed ones. Indexes not started before ctx is done fail with ctx.Err().
+func forEachConcurrent(ctx context.Context, n, limit int, fn func(ctx context.Context, i int) error) error {
+	if limit < 1 {
+		limit = 1
+	}
+
+	errs := make([]error, n)
+	sem := make(chan struct{}, limit)
+	wg := new(sync.WaitGroup)
+
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			errs[i] = fn(ctx, i)
+		}(i)
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
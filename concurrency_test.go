@@ -0,0 +1,62 @@
+package googletrends
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForEachConcurrent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("bounds parallel calls", func(t *testing.T) {
+		var running, peak, calls int32
+		err := forEachConcurrent(context.Background(), 10, 3, func(ctx context.Context, i int) error {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			atomic.AddInt32(&calls, 1)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(10), calls)
+		assert.LessOrEqual(t, peak, int32(3))
+	})
+
+	t.Run("joins errors", func(t *testing.T) {
+		errOdd := errors.New("odd")
+		err := forEachConcurrent(context.Background(), 4, 2, func(ctx context.Context, i int) error {
+			if i%2 == 1 {
+				return errOdd
+			}
+			return nil
+		})
+
+		assert.ErrorIs(t, err, errOdd)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var calls int32
+		err := forEachConcurrent(ctx, 3, 1, func(ctx context.Context, i int) error {
+			atomic.AddInt32(&calls, 1)
+			return nil
+		})
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.LessOrEqual(t, calls, int32(3))
+	})
+}
@@ -1,6 +1,10 @@
 package googletrends
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
 
 // Internal error message constants used for error wrapping.
 // These provide context when wrapping errors from underlying operations.
@@ -49,4 +53,89 @@ var (
 	//
 	// Use ExploreResponse.GetWidgetsByType() to get the correct widget type.
 	ErrInvalidWidgetType = errors.New("invalid widget type")
+
+	// ErrMissingToken indicates that a widget has no token or one too short to be valid,
+	// e.g. because it was truncated when stored. The follow-up call is not sent.
+	// Re-run Explore to obtain fresh widgets.
+	ErrMissingToken = errors.New("widget token missing, re-run Explore")
+
+	// ErrTokenExpired indicates that Google accepted a widget data request but
+	// returned no data payload, which happens when the widget token has expired.
+	//
+	// Widget tokens are short-lived. Re-run Explore to obtain fresh widgets and
+	// retry the call with them.
+	ErrTokenExpired = errors.New("widget token expired")
+
+	// ErrMissingRequest indicates that a widget has no request payload.
+	//
+	// Google occasionally returns widgets with a null request; such widgets can't
+	// be fetched. Drop them from Explore results with WithSkipEmptyWidgets.
+	ErrMissingRequest = errors.New("widget has no request payload")
+
+	// ErrConsentRequired indicates that Google redirected the request to its HTML
+	// consent page instead of serving data, which happens for clients in some regions.
+	//
+	// Accept the consent dialog on trends.google.com in a browser once and pass its
+	// SOCS or CONSENT cookie with WithConsentCookie.
+	ErrConsentRequired = errors.New("google consent required, see WithConsentCookie")
+
+	// ErrBlocked indicates that Google served a captcha or block page instead of data.
+	// It is reported by ValidateNotBlocked, see WithResponseValidator.
+	ErrBlocked = errors.New("response looks like a block page")
+
+	// ErrInvalidGeoMap indicates that the parallel slices of a GeoMap don't match
+	// the number of keywords passed to GeoMap.Entries.
+	ErrInvalidGeoMap = errors.New("invalid geo map data")
+
+	// ErrInvalidTimeline indicates that timeline data can't be processed by a helper.
+	//
+	// This error occurs when a Timeline point has an unparseable Time value,
+	// when two points share the same timestamp, or when points of a frame
+	// have a different number of values.
+	ErrInvalidTimeline = errors.New("invalid timeline data")
 )
+
+// parseSnippetLen is the maximum number of bytes of the unparseable data kept in ParseError.
+const parseSnippetLen = 200
+
+// ParseError is returned when a Google Trends response can't be parsed as JSON.
+// It carries the beginning of the offending data, which usually tells right away
+// whether Google changed the format or returned an HTML error page.
+//
+// Use errors.As to inspect it:
+//
+//	var parseErr *googletrends.ParseError
+//	if errors.As(err, &parseErr) {
+//	    log.Printf("unexpected response: %s", parseErr.Snippet)
+//	}
+type ParseError struct {
+	// Snippet is the first bytes (at most 200) of the data that failed to parse,
+	// truncated on a UTF-8 character boundary.
+	Snippet string
+
+	// Err is the underlying JSON error.
+	Err error
+}
+
+// newParseError creates a ParseError keeping a safely truncated snippet of data.
+func newParseError(data string, err error) *ParseError {
+	if len(data) > parseSnippetLen {
+		n := parseSnippetLen
+		for n > 0 && !utf8.RuneStart(data[n]) {
+			n--
+		}
+		data = data[:n]
+	}
+
+	return &ParseError{Snippet: data, Err: err}
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: %v: data %q", errParsing, e.Err, e.Snippet)
+}
+
+// Unwrap returns the underlying JSON error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,102 @@
+package googletrends
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// relatedCSVHeader is the header row written by RelatedToCSV.
+var relatedCSVHeader = []string{"query", "value", "formatted", "rising", "link"}
+
+// RelatedToCSV writes related queries or topics as CSV for keyword research spreadsheets,
+// with a header row and the columns query, value, formatted, rising and link.
+//
+// For related topics the query column holds the topic title. rising is "true" for
+// rising entries (growth percentages and "Breakout", see FilterByMinValue), and link
+// is the absolute URL returned by RankedKeyword.FullLink. Nil entries are skipped.
+//
+// Example:
+//
+//	related, _ := googletrends.Related(ctx, widget, "EN")
+//	f, _ := os.Create("related.csv")
+//	defer f.Close()
+//	if err := googletrends.RelatedToCSV(f, related); err != nil {
+//	    log.Fatal(err)
+//	}
+func RelatedToCSV(w io.Writer, keywords []*RankedKeyword) error {
+	return std().RelatedToCSV(w, keywords)
+}
+
+// RelatedToCSV is like the package-level RelatedToCSV, using the link base of the client c.
+func (c *Client) RelatedToCSV(w io.Writer, keywords []*RankedKeyword) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(relatedCSVHeader); err != nil {
+		return err
+	}
+
+	for _, k := range keywords {
+		if k == nil {
+			continue
+		}
+
+		if err := cw.Write([]string{
+			k.term(),
+			strconv.Itoa(k.Value),
+			k.FormattedValue,
+			strconv.FormatBool(k.isRising()),
+			c.FullLink(k),
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// Save writes the category tree as JSON, so a snapshot of ExploreCategories can be
+// baked into a build and loaded back with LoadCategories.
+//
+// Example:
+//
+//	categories, _ := googletrends.ExploreCategories(ctx)
+//	f, _ := os.Create("categories.json")
+//	defer f.Close()
+//	if err := categories.Save(f); err != nil {
+//	    log.Fatal(err)
+//	}
+func (t *ExploreCatTree) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(t)
+}
+
+// LoadCategories reads a category tree written by ExploreCatTree.Save.
+// Combined with WithCategorySnapshot it avoids fetching the flaky category picker
+// endpoint at run time.
+//
+// Returns a ParseError if the data is not a valid category tree.
+//
+// Example:
+//
+//	f, _ := os.Open("categories.json")
+//	defer f.Close()
+//	categories, err := googletrends.LoadCategories(f)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	googletrends.Configure(googletrends.WithCategorySnapshot(categories))
+func LoadCategories(r io.Reader) (*ExploreCatTree, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	out := new(ExploreCatTree)
+	if err := json.Unmarshal(b, out); err != nil {
+		return nil, newParseError(string(b), err)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,83 @@
+package googletrends
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingWriter is an io.Writer that always fails.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestRelatedToCSV(t *testing.T) {
+	keywords := []*RankedKeyword{
+		{Query: "golang tutorial", Value: 100, FormattedValue: "100", Link: "/trends/explore?q=golang+tutorial"},
+		nil,
+		{Query: `golang "generics", explained`, Value: 250, FormattedValue: "+250%", Link: "/trends/explore?q=golang+generics"},
+		{Topic: KeywordTopic{Mid: "/m/09gbxjr", Title: "Go"}, Value: 45050, FormattedValue: "Breakout", Link: "https://trends.google.com/trends/explore?q=/m/09gbxjr", rising: true},
+		{Query: "golang mascot", Value: 0, FormattedValue: "<1", Link: "/trends/explore?q=golang+mascot"},
+	}
+
+	var buf bytes.Buffer
+	err := RelatedToCSV(&buf, keywords)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "query,value,formatted,rising,link\n"+
+		"golang tutorial,100,100,false,https://trends.google.com/trends/explore?q=golang+tutorial\n"+
+		`"golang ""generics"", explained",250,+250%,true,https://trends.google.com/trends/explore?q=golang+generics`+"\n"+
+		"Go,45050,Breakout,true,https://trends.google.com/trends/explore?q=/m/09gbxjr\n"+
+		"golang mascot,0,<1,false,https://trends.google.com/trends/explore?q=golang+mascot\n", buf.String())
+
+	assert.Error(t, RelatedToCSV(failingWriter{}, keywords))
+}
+
+func TestCategorySnapshot(t *testing.T) {
+	tree := &ExploreCatTree{Name: "All categories", ID: 0, Children: []*ExploreCatTree{
+		{Name: "Computers & Electronics", ID: 5, Children: []*ExploreCatTree{{Name: "Programming", ID: catProgramming}}},
+	}}
+
+	t.Run("round trip", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, tree.Save(&buf))
+
+		loaded, err := LoadCategories(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, tree, loaded)
+	})
+
+	t.Run("invalid snapshot", func(t *testing.T) {
+		_, err := LoadCategories(strings.NewReader(`{"name":`))
+
+		var parseErr *ParseError
+		assert.True(t, errors.As(err, &parseErr))
+	})
+
+	t.Run("save error", func(t *testing.T) {
+		assert.Error(t, tree.Save(failingWriter{}))
+	})
+
+	t.Run("seeds the cache", func(t *testing.T) {
+		var calls int
+		c := useMockClient(t, func(req *http.Request) (*http.Response, error) {
+			calls++
+			return newMockResponse(http.StatusInternalServerError, ""), nil
+		})
+		WithCategorySnapshot(tree)(c)
+
+		cats, err := ExploreCategories(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, tree, cats)
+		assert.Zero(t, calls)
+	})
+}
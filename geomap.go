@@ -0,0 +1,227 @@
+package googletrends
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// geoMapJSON has the fields of GeoMap without its UnmarshalJSON method.
+type geoMapJSON GeoMap
+
+// UnmarshalJSON decodes a region, also accepting the snake_case field names
+// (geo_code, geo_name, formatted_value, max_value_index, has_data) some regions and
+// API versions return instead of camelCase. Other casings of the camelCase names are
+// matched by encoding/json already. When both forms are present, camelCase wins.
+func (g *GeoMap) UnmarshalJSON(b []byte) error {
+	aux := struct {
+		*geoMapJSON
+		GeoCode        string   `json:"geo_code"`
+		GeoName        string   `json:"geo_name"`
+		FormattedValue []string `json:"formatted_value"`
+		MaxValueIndex  *int     `json:"max_value_index"`
+		HasData        []bool   `json:"has_data"`
+	}{geoMapJSON: (*geoMapJSON)(g)}
+
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+
+	if len(g.GeoCode) == 0 {
+		g.GeoCode = aux.GeoCode
+	}
+	if len(g.GeoName) == 0 {
+		g.GeoName = aux.GeoName
+	}
+	if g.FormattedValue == nil {
+		g.FormattedValue = aux.FormattedValue
+	}
+	if g.MaxValueIndex == 0 && aux.MaxValueIndex != nil {
+		g.MaxValueIndex = *aux.MaxValueIndex
+	}
+	if g.HasData == nil {
+		g.HasData = aux.HasData
+	}
+
+	return nil
+}
+
+// hasValue reports whether the region has data for the keyword at index i.
+// Regions whose Value or HasData slices are too short for i are treated as having no data.
+func (g *GeoMap) hasValue(i int) bool {
+	return g != nil && i >= 0 && i < len(g.Value) && i < len(g.HasData) && g.HasData[i]
+}
+
+// TopRegion returns the region with the highest interest for the keyword at keywordIndex.
+// It is the quickest way to answer "where is this keyword most popular?".
+//
+// Regions without data for the keyword (HasData false or slices shorter than
+// keywordIndex) are skipped. When several regions share the maximum, the first one wins.
+// The boolean result is false when no region has data for the keyword.
+//
+// Example:
+//
+//	regions, _ := googletrends.InterestByLocation(ctx, geoWidget, "EN")
+//	if top, ok := googletrends.TopRegion(regions, 0); ok {
+//	    fmt.Printf("Most popular in %s (%d)\n", top.GeoName, top.Value[0])
+//	}
+func TopRegion(data []*GeoMap, keywordIndex int) (*GeoMap, bool) {
+	var top *GeoMap
+	for _, v := range data {
+		if !v.hasValue(keywordIndex) {
+			continue
+		}
+
+		if top == nil || v.Value[keywordIndex] > top.Value[keywordIndex] {
+			top = v
+		}
+	}
+
+	return top, top != nil
+}
+
+// DisplayName returns the label to show for the region: GeoName, or GeoCode
+// when Google returned the region without a name, as happens for some
+// city-level entries.
+func (g *GeoMap) DisplayName() string {
+	if len(g.GeoName) != 0 {
+		return g.GeoName
+	}
+
+	return g.GeoCode
+}
+
+// GeoEntry is the self-describing value of one compared keyword in a region.
+type GeoEntry struct {
+	// Keyword is the compared keyword the values belong to.
+	Keyword string
+
+	// Value is the interest value (0-100) of the keyword in the region.
+	Value int
+
+	// Formatted is the display-ready form of Value.
+	Formatted string
+
+	// HasData indicates whether data is available for the keyword in the region.
+	HasData bool
+}
+
+// Entries zips the parallel Value, FormattedValue and HasData slices of the region
+// with the compared keyword names, in comparison order. This avoids index juggling
+// when rendering multi-keyword geo data.
+//
+// Returns ErrInvalidGeoMap if any of the slices has a different length than keywords.
+//
+// Example:
+//
+//	for _, region := range regions {
+//	    entries, err := region.Entries([]string{"go", "rust"})
+//	    if err != nil {
+//	        log.Fatal(err)
+//	    }
+//	    for _, e := range entries {
+//	        fmt.Printf("%s %s: %s\n", region.DisplayName(), e.Keyword, e.Formatted)
+//	    }
+//	}
+func (g *GeoMap) Entries(keywords []string) ([]GeoEntry, error) {
+	if len(g.Value) != len(keywords) || len(g.FormattedValue) != len(keywords) || len(g.HasData) != len(keywords) {
+		return nil, fmt.Errorf("%w: %d keywords, %d values, %d formatted values, %d data flags",
+			ErrInvalidGeoMap, len(keywords), len(g.Value), len(g.FormattedValue), len(g.HasData))
+	}
+
+	out := make([]GeoEntry, 0, len(keywords))
+	for i, k := range keywords {
+		out = append(out, GeoEntry{
+			Keyword:   k,
+			Value:     g.Value[i],
+			Formatted: g.FormattedValue[i],
+			HasData:   g.HasData[i],
+		})
+	}
+
+	return out, nil
+}
+
+// HasAnyData reports whether any region of data has data for any keyword
+// (a HasData flag set), so maps without any data can be skipped before rendering.
+//
+// Example:
+//
+//	regions, _ := googletrends.InterestByLocation(ctx, widget, "EN")
+//	if !googletrends.GeoMaps(regions).HasAnyData() {
+//	    return // nothing to map
+//	}
+func (data GeoMaps) HasAnyData() bool {
+	for _, v := range data {
+		if v == nil {
+			continue
+		}
+
+		for k := range v.Value {
+			if v.hasValue(k) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// FilterHasData returns the regions of data that have data for the keyword at
+// keywordIndex, keeping their order, for maps that should omit regions without data
+// rather than show them as zero. InterestByLocation itself keeps every region.
+//
+// Regions whose Value or HasData slices are too short for keywordIndex, nil regions
+// and any region for a negative keywordIndex are left out.
+//
+// Example:
+//
+//	regions, _ := googletrends.InterestByLocation(ctx, widget, "EN")
+//	for _, r := range googletrends.GeoMaps(regions).FilterHasData(0) {
+//	    fmt.Println(r.DisplayName(), r.Value[0])
+//	}
+func (data GeoMaps) FilterHasData(keywordIndex int) GeoMaps {
+	out := make(GeoMaps, 0, len(data))
+	for _, v := range data {
+		if v.hasValue(keywordIndex) {
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
+// RegionAverages returns, for every region, the mean interest across all compared
+// keywords, keyed by GeoCode. It gives a single "overall interest" value per region
+// for rendering one heatmap of a multi-keyword comparison.
+//
+// Only values with data (HasData true) are averaged, and regions without data for
+// any keyword are left out rather than recorded as zero.
+//
+// Example:
+//
+//	regions, _ := googletrends.InterestByLocation(ctx, geoWidget, "EN")
+//	for code, avg := range googletrends.RegionAverages(regions) {
+//	    fmt.Printf("%s: %.1f\n", code, avg)
+//	}
+func RegionAverages(data []*GeoMap) map[string]float64 {
+	out := make(map[string]float64, len(data))
+	for _, v := range data {
+		if v == nil {
+			continue
+		}
+
+		var sum, n int
+		for k := range v.Value {
+			if v.hasValue(k) {
+				sum += v.Value[k]
+				n++
+			}
+		}
+
+		if n > 0 {
+			out[v.GeoCode] = float64(sum) / float64(n)
+		}
+	}
+
+	return out
+}
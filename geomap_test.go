@@ -0,0 +1,340 @@
+package googletrends
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopRegion(t *testing.T) {
+	t.Parallel()
+
+	data := []*GeoMap{
+		{GeoCode: "US-CA", Value: []int{80, 10}, HasData: []bool{true, true}},
+		{GeoCode: "US-NY", Value: []int{100, 5}, HasData: []bool{false, true}},
+		{GeoCode: "US-TX", Value: []int{90}, HasData: []bool{true}},
+		nil,
+		{GeoCode: "US-WA", Value: []int{90, 40}, HasData: []bool{true, true}},
+	}
+
+	tests := []struct {
+		name         string
+		data         []*GeoMap
+		index        int
+		expectedCode string
+		expectedOK   bool
+	}{
+		{
+			name:         "skips no data and keeps first max",
+			data:         data,
+			index:        0,
+			expectedCode: "US-TX",
+			expectedOK:   true,
+		},
+		{
+			name:         "skips entries too short for index",
+			data:         data,
+			index:        1,
+			expectedCode: "US-WA",
+			expectedOK:   true,
+		},
+		{
+			name:       "index out of range for every entry",
+			data:       data,
+			index:      5,
+			expectedOK: false,
+		},
+		{
+			name:       "negative index",
+			data:       data,
+			index:      -1,
+			expectedOK: false,
+		},
+		{
+			name:       "empty input",
+			data:       nil,
+			index:      0,
+			expectedOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			top, ok := TopRegion(tt.data, tt.index)
+
+			assert.Equal(t, tt.expectedOK, ok)
+			if tt.expectedOK {
+				assert.Equal(t, tt.expectedCode, top.GeoCode)
+			} else {
+				assert.Nil(t, top)
+			}
+		})
+	}
+}
+
+func TestGeoMapDisplayName(t *testing.T) {
+	useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		return newMockResponse(http.StatusOK, `)]}',{"default":{"geoMapData":[`+
+			`{"geoCode":"US-CA","geoName":"California","value":[100],"hasData":[true]},`+
+			`{"geoCode":"1014221","value":[80],"hasData":[true]},`+
+			`{"geoName":"Austin","value":[60],"hasData":[true]}]}}`), nil
+	})
+
+	w := &ExploreWidget{
+		ID:      string(IntOverRegionID),
+		Token:   testToken,
+		Request: &WidgetResponse{Geo: map[string]string{"country": locUS}, Resolution: "CITY"},
+	}
+
+	regions, err := InterestByLocation(context.Background(), w, langEN)
+	require.NoError(t, err)
+	require.Len(t, regions, 3)
+
+	names := make([]string, 0, len(regions))
+	for _, r := range regions {
+		names = append(names, r.DisplayName())
+	}
+	assert.Equal(t, []string{"California", "1014221", "Austin"}, names)
+}
+
+func TestGeoMapEntries(t *testing.T) {
+	t.Parallel()
+
+	region := &GeoMap{
+		GeoCode:        "US-CA",
+		Value:          []int{100, 0},
+		FormattedValue: []string{"100", "<1"},
+		HasData:        []bool{true, false},
+	}
+
+	t.Run("zips keywords with values", func(t *testing.T) {
+		entries, err := region.Entries([]string{"go", "rust"})
+
+		require.NoError(t, err)
+		assert.Equal(t, []GeoEntry{
+			{Keyword: "go", Value: 100, Formatted: "100", HasData: true},
+			{Keyword: "rust", Value: 0, Formatted: "<1", HasData: false},
+		}, entries)
+	})
+
+	t.Run("keyword count mismatch", func(t *testing.T) {
+		_, err := region.Entries([]string{"go"})
+
+		assert.ErrorIs(t, err, ErrInvalidGeoMap)
+	})
+
+	t.Run("parallel slices mismatch", func(t *testing.T) {
+		broken := &GeoMap{Value: []int{1, 2}, FormattedValue: []string{"1"}, HasData: []bool{true, true}}
+		_, err := broken.Entries([]string{"go", "rust"})
+
+		assert.ErrorIs(t, err, ErrInvalidGeoMap)
+	})
+}
+
+func TestInterestByLocationDMA(t *testing.T) {
+	var sent *WidgetResponse
+	useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		sent = new(WidgetResponse)
+		if err := json.Unmarshal([]byte(req.URL.Query().Get(paramReq)), sent); err != nil {
+			return nil, err
+		}
+		return newMockResponse(http.StatusOK, `)]}',{"default":{"geoMapData":[`+
+			`{"geoCode":"807","geoName":"San Francisco-Oakland-San Jose CA","value":[100],"formattedValue":["100"],"hasData":[true]},`+
+			`{"geoCode":"819","geoName":"Seattle-Tacoma WA","value":[84],"formattedValue":["84"],"hasData":[true]}]}}`), nil
+	})
+
+	newWidget := func(geo interface{}) *ExploreWidget {
+		return &ExploreWidget{
+			ID:      string(IntOverRegionID),
+			Token:   testToken,
+			Request: &WidgetResponse{Geo: geo, Resolution: "REGION"},
+		}
+	}
+
+	t.Run("US widget", func(t *testing.T) {
+		w := newWidget(map[string]interface{}{"country": locUS})
+		regions, err := InterestByLocation(context.Background(), w, langEN, WithDMA())
+
+		require.NoError(t, err)
+		assert.Equal(t, resolutionDMA, sent.Resolution)
+		assert.Equal(t, "REGION", w.Request.Resolution)
+		require.Len(t, regions, 2)
+		assert.Equal(t, "807", regions[0].GeoCode)
+		assert.Equal(t, "San Francisco-Oakland-San Jose CA", regions[0].DisplayName())
+	})
+
+	for name, geo := range map[string]interface{}{
+		"non-US widget":    map[string]string{"country": "GB"},
+		"worldwide widget": map[string]interface{}{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			sent = nil
+			_, err := InterestByLocation(context.Background(), newWidget(geo), langEN, WithDMA())
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "DMA")
+			assert.Nil(t, sent)
+		})
+	}
+}
+
+func TestInterestByLocationWorldwide(t *testing.T) {
+	var sent *WidgetResponse
+	useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		sent = new(WidgetResponse)
+		if err := json.Unmarshal([]byte(req.URL.Query().Get(paramReq)), sent); err != nil {
+			return nil, err
+		}
+		return newMockResponse(http.StatusOK, `)]}',{"default":{"geoMapData":[`+
+			`{"geoCode":"US","geoName":"United States","value":[100],"formattedValue":["100"],"hasData":[true]},`+
+			`{"geoCode":"GB","geoName":"United Kingdom","value":[72],"formattedValue":["72"],"hasData":[true]}]}}`), nil
+	})
+
+	newWidget := func(geo interface{}) *ExploreWidget {
+		return &ExploreWidget{
+			ID:      string(IntOverRegionID),
+			Token:   testToken,
+			Request: &WidgetResponse{Geo: geo, Resolution: "REGION"},
+		}
+	}
+
+	for name, geo := range map[string]interface{}{
+		"nil geo":   nil,
+		"empty geo": map[string]interface{}{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := newWidget(geo)
+			regions, err := InterestByLocation(context.Background(), w, langEN)
+
+			require.NoError(t, err)
+			assert.Equal(t, resolutionCountry, sent.Resolution)
+			assert.Equal(t, "REGION", w.Request.Resolution)
+			require.Len(t, regions, 2)
+			assert.Equal(t, "US", regions[0].GeoCode)
+			assert.Equal(t, "GB", regions[1].GeoCode)
+		})
+	}
+
+	t.Run("country widget keeps resolution", func(t *testing.T) {
+		_, err := InterestByLocation(context.Background(), newWidget(map[string]string{"country": "GB"}), langEN)
+
+		require.NoError(t, err)
+		assert.Equal(t, "REGION", sent.Resolution)
+	})
+}
+
+func TestGeoMapsHasAnyData(t *testing.T) {
+	t.Parallel()
+
+	empty := &GeoMap{GeoCode: "US-CA", Value: []int{0, 0}, HasData: []bool{false, false}}
+
+	assert.False(t, GeoMaps(nil).HasAnyData())
+	assert.False(t, GeoMaps{empty, nil}.HasAnyData())
+	assert.False(t, GeoMaps{{Value: []int{5}, HasData: []bool{false, true}}}.HasAnyData())
+	assert.True(t, GeoMaps{empty, {GeoCode: "US-NY", Value: []int{0, 7}, HasData: []bool{false, true}}}.HasAnyData())
+}
+
+func TestGeoMapsFilterHasData(t *testing.T) {
+	t.Parallel()
+
+	ca := &GeoMap{GeoCode: "US-CA", Value: []int{80, 10}, HasData: []bool{true, true}}
+	ny := &GeoMap{GeoCode: "US-NY", Value: []int{0, 5}, HasData: []bool{false, true}}
+	tx := &GeoMap{GeoCode: "US-TX", Value: []int{90}, HasData: []bool{true}}
+	data := GeoMaps{ca, ny, nil, tx}
+
+	tests := []struct {
+		name         string
+		keywordIndex int
+		expected     GeoMaps
+	}{
+		{
+			name:         "omits regions without data",
+			keywordIndex: 0,
+			expected:     GeoMaps{ca, tx},
+		},
+		{
+			name:         "omits regions too short for index",
+			keywordIndex: 1,
+			expected:     GeoMaps{ca, ny},
+		},
+		{
+			name:         "index out of range",
+			keywordIndex: 2,
+			expected:     GeoMaps{},
+		},
+		{
+			name:         "negative index",
+			keywordIndex: -1,
+			expected:     GeoMaps{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, data.FilterHasData(tt.keywordIndex))
+		})
+	}
+
+	// the input keeps every region
+	assert.Len(t, data, 4)
+	assert.Empty(t, GeoMaps(nil).FilterHasData(0))
+}
+
+func TestRegionAverages(t *testing.T) {
+	t.Parallel()
+
+	data := []*GeoMap{
+		{GeoCode: "US-CA", Value: []int{80, 10, 30}, HasData: []bool{true, true, true}},
+		{GeoCode: "US-NY", Value: []int{100, 5}, HasData: []bool{false, true}},
+		{GeoCode: "US-TX", Value: []int{0, 0}, HasData: []bool{false, false}},
+		nil,
+		{GeoCode: "US-WA", Value: []int{90, 40}, HasData: []bool{true}},
+	}
+
+	assert.Equal(t, map[string]float64{
+		"US-CA": 40,
+		"US-NY": 5,
+		"US-WA": 90,
+	}, RegionAverages(data))
+	assert.Empty(t, RegionAverages(nil))
+}
+
+func TestGeoMapUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	expected := &GeoMap{
+		GeoCode:        "US-CA",
+		GeoName:        "California",
+		Value:          []int{80, 100},
+		FormattedValue: []string{"80", "100"},
+		MaxValueIndex:  1,
+		HasData:        []bool{true, true},
+	}
+
+	fixtures := map[string]string{
+		"camelCase": `{"geoCode":"US-CA","geoName":"California","value":[80,100],` +
+			`"formattedValue":["80","100"],"maxValueIndex":1,"hasData":[true,true]}`,
+		"snake_case": `{"geo_code":"US-CA","geo_name":"California","value":[80,100],` +
+			`"formatted_value":["80","100"],"max_value_index":1,"has_data":[true,true]}`,
+	}
+
+	for name, fixture := range fixtures {
+		t.Run(name, func(t *testing.T) {
+			out := new(GeoMap)
+			require.NoError(t, json.Unmarshal([]byte(fixture), out))
+			assert.Equal(t, expected, out)
+		})
+	}
+
+	t.Run("decoded from response", func(t *testing.T) {
+		out := new(geoOut)
+		require.NoError(t, json.Unmarshal([]byte(`{"default":{"geoMapData":[{"geo_code":"US-NY","has_data":[false]}]}}`), out))
+		require.Len(t, out.Default.GeoMapData, 1)
+		assert.Equal(t, "US-NY", out.Default.GeoMapData[0].GeoCode)
+		assert.Equal(t, []bool{false}, out.Default.GeoMapData[0].HasData)
+	})
+}
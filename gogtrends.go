@@ -3,14 +3,153 @@ package googletrends
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
-// client is the package-level Google Trends client instance.
-// It is initialized with default settings and is safe for concurrent use.
-var client = newGClient()
+var (
+	// client is the package-level Google Trends client instance.
+	// It is initialized with default settings and is safe for concurrent use.
+	// Access it through defaultClient, since Configure may replace it.
+	client = newGClient()
+
+	// clientMu protects replacing the package-level client.
+	clientMu = new(sync.RWMutex)
+)
+
+// defaultClient returns the current package-level client.
+func defaultClient() *gClient {
+	clientMu.RLock()
+	defer clientMu.RUnlock()
+	return client
+}
+
+// setDefaultClient replaces the package-level client and returns the previous one.
+func setDefaultClient(c *gClient) *gClient {
+	clientMu.Lock()
+	defer clientMu.Unlock()
+	prev := client
+	client = c
+	return prev
+}
+
+// Client is a Google Trends client with its own HTTP client, options, caches and
+// cookies. Its methods mirror the package-level functions, which use a shared
+// package-level client configured with Configure. Create separate clients to keep
+// independent state in one process, e.g. one behind a US proxy and one behind an
+// EU proxy. A Client is safe for concurrent use.
+//
+// Example:
+//
+//	us := googletrends.NewClient(googletrends.WithHTTPClient(usProxyClient))
+//	eu := googletrends.NewClient(googletrends.WithHTTPClient(euProxyClient))
+//	usTrends, _ := us.Daily(ctx, "EN", "US")
+//	deTrends, _ := eu.Daily(ctx, "DE", "DE")
+type Client struct {
+	*gClient
+}
+
+// NewClient creates a Client from the default settings and the given options.
+func NewClient(opts ...Option) *Client {
+	return &Client{gClient: newGClient(opts...)}
+}
+
+// std returns a Client using the current package-level client.
+func std() *Client {
+	return &Client{gClient: defaultClient()}
+}
+
+// Configure replaces the package-level client used by the package functions with
+// a new one built from the default settings and the given options. Use it to set
+// options such as WithHTTPClient globally.
+//
+// Configure is safe to call concurrently with requests: requests already in flight
+// finish on the previous client, later calls use the new one. Options are not
+// cumulative across calls, and the new client starts with empty caches and cookies
+// and with debug logging disabled.
+//
+// Example:
+//
+//	googletrends.Configure(googletrends.WithHTTPClient(&http.Client{Timeout: 10 * time.Second}))
+func Configure(opts ...Option) {
+	setDefaultClient(newGClient(opts...))
+}
+
+// Reset restores the package-level client to the default settings, discarding
+// options set with Configure as well as cached data and cookies.
+// It is mainly intended for tests.
+func Reset() {
+	Configure()
+}
+
+// rateLimitWindow is how long after an HTTP 429 response RateLimited reports true.
+const rateLimitWindow = time.Minute
+
+// RateLimited reports whether the package-level client received an HTTP 429 response
+// within the last minute. Adaptive scrapers can check it to back off proactively
+// instead of parsing request errors.
+//
+// Example:
+//
+//	if googletrends.RateLimited() {
+//	    time.Sleep(time.Minute)
+//	}
+func RateLimited() bool {
+	return std().RateLimited()
+}
+
+// RateLimited is like the package-level RateLimited, using the client c.
+func (c *Client) RateLimited() bool {
+	last := c.getLastRateLimit()
+
+	return !last.IsZero() && time.Since(last) < rateLimitWindow
+}
+
+// LastRateLimit returns the time the package-level client last received an HTTP 429
+// response, or the zero time if it never did.
+//
+// Example:
+//
+//	if last := googletrends.LastRateLimit(); !last.IsZero() {
+//	    fmt.Println("rate limited", time.Since(last), "ago")
+//	}
+func LastRateLimit() time.Time {
+	return std().LastRateLimit()
+}
+
+// LastRateLimit is like the package-level LastRateLimit, using the client c.
+func (c *Client) LastRateLimit() time.Time {
+	return c.getLastRateLimit()
+}
+
+// RecentResponses returns the raw responses recorded by the package-level client,
+// from the oldest to the most recent. It returns an empty slice unless the client was
+// configured with WithResponseBuffer. The bodies must not be modified.
+//
+// Example:
+//
+//	googletrends.Configure(googletrends.WithResponseBuffer(10))
+//	_, err := googletrends.Explore(ctx, request, "EN")
+//	for _, r := range googletrends.RecentResponses() {
+//	    fmt.Println(r.Endpoint, r.StatusCode, len(r.Body))
+//	}
+func RecentResponses() []RawResponse {
+	return std().RecentResponses()
+}
+
+// RecentResponses is like the package-level RecentResponses, using the client c.
+func (c *Client) RecentResponses() []RawResponse {
+	if c.responses == nil {
+		return []RawResponse{}
+	}
+
+	return c.responses.list()
+}
 
 // Debug enables or disables debug logging for the Google Trends client.
 // When enabled, request URLs, payloads, and response details are logged to stdout.
@@ -23,7 +162,12 @@ var client = newGClient()
 //	trends, _ := googletrends.Daily(ctx, "EN", "US")
 //	googletrends.Debug(false) // Disable debug logging
 func Debug(debug bool) {
-	client.debug = debug
+	std().Debug(debug)
+}
+
+// Debug is like the package-level Debug, using the client c.
+func (c *Client) Debug(debug bool) {
+	c.debug = debug
 }
 
 // Daily retrieves daily trending searches for a specific language and location.
@@ -50,7 +194,12 @@ func Debug(debug bool) {
 //	    fmt.Println(trend.Title.Query)
 //	}
 func Daily(ctx context.Context, hl, loc string) ([]*TrendingSearch, error) {
-	return DailyNew(ctx, hl, loc)
+	return std().Daily(ctx, hl, loc)
+}
+
+// Daily is like the package-level Daily, using the client c.
+func (c *Client) Daily(ctx context.Context, hl, loc string) ([]*TrendingSearch, error) {
+	return c.DailyNew(ctx, hl, loc)
 }
 
 // ExploreCategories retrieves the complete tree of available Google Trends categories.
@@ -60,7 +209,8 @@ func Daily(ctx context.Context, hl, loc string) ([]*TrendingSearch, error) {
 // to specific topics like "Arts & Entertainment", "Business", "Technology", etc.
 //
 // Deprecated: This function uses the old Google Trends API which may be unstable.
-// Consider using hardcoded category IDs for common categories instead.
+// Consider using hardcoded category IDs for common categories instead, or a snapshot
+// saved with ExploreCatTree.Save and seeded with WithCategorySnapshot.
 //
 // Example:
 //
@@ -73,13 +223,18 @@ func Daily(ctx context.Context, hl, loc string) ([]*TrendingSearch, error) {
 //	    fmt.Printf("ID: %d, Name: %s\n", cat.ID, cat.Name)
 //	}
 func ExploreCategories(ctx context.Context) (*ExploreCatTree, error) {
-	if cats := client.getCategories(); cats != nil {
+	return std().ExploreCategories(ctx)
+}
+
+// ExploreCategories is like the package-level ExploreCategories, using the client c.
+func (c *Client) ExploreCategories(ctx context.Context) (*ExploreCatTree, error) {
+	if cats := c.getCategories(); cats != nil {
 		return cats, nil
 	}
 
 	u, _ := url.Parse(gAPI + gSCategories)
 
-	b, err := client.do(ctx, u)
+	b, err := c.do(ctx, u)
 	if err != nil {
 		return nil, err
 	}
@@ -88,12 +243,12 @@ func ExploreCategories(ctx context.Context) (*ExploreCatTree, error) {
 	str := strings.Replace(string(b), ")]}'", "", 1)
 
 	out := new(ExploreCatTree)
-	if err := client.unmarshal(str, out); err != nil {
+	if err := c.unmarshal(str, out); err != nil {
 		return nil, err
 	}
 
 	// cache in client
-	client.setCategories(out)
+	c.setCategories(out)
 
 	return out, nil
 }
@@ -118,13 +273,18 @@ func ExploreCategories(ctx context.Context) (*ExploreCatTree, error) {
 //	    fmt.Printf("Code: %s, Name: %s\n", loc.ID, loc.Name)
 //	}
 func ExploreLocations(ctx context.Context) (*ExploreLocTree, error) {
-	if locs := client.getLocations(); locs != nil {
+	return std().ExploreLocations(ctx)
+}
+
+// ExploreLocations is like the package-level ExploreLocations, using the client c.
+func (c *Client) ExploreLocations(ctx context.Context) (*ExploreLocTree, error) {
+	if locs := c.getLocations(); locs != nil {
 		return locs, nil
 	}
 
 	u, _ := url.Parse(gAPI + gSGeo)
 
-	b, err := client.do(ctx, u)
+	b, err := c.do(ctx, u)
 	if err != nil {
 		return nil, err
 	}
@@ -133,12 +293,12 @@ func ExploreLocations(ctx context.Context) (*ExploreLocTree, error) {
 	str := strings.Replace(string(b), ")]}'", "", 1)
 
 	out := new(ExploreLocTree)
-	if err := client.unmarshal(str, out); err != nil {
+	if err := c.unmarshal(str, out); err != nil {
 		return nil, err
 	}
 
 	// cache in client
-	client.setLocations(out)
+	c.setLocations(out)
 
 	return out, nil
 }
@@ -159,6 +319,8 @@ func ExploreLocations(ctx context.Context) (*ExploreLocTree, error) {
 //   - hl: Host language code (e.g., "EN", "RU")
 //
 // Returns ExploreResponse (slice of widgets) or an error if the request fails.
+// Widgets Google returns more than once are dropped, see ExploreResponse.Deduplicate.
+// With WithExploreCache, repeated identical requests are served from memory.
 //
 // Example:
 //
@@ -178,19 +340,66 @@ func ExploreLocations(ctx context.Context) (*ExploreLocTree, error) {
 //	timeWidgets := widgets.GetWidgetsByType(googletrends.IntOverTimeWidgetID)
 //	timeline, _ := googletrends.InterestOverTime(ctx, timeWidgets[0], "EN")
 func Explore(ctx context.Context, r *ExploreRequest, hl string) (ExploreResponse, error) {
-	// hook for using incorrect `time` request (backward compatibility)
-	for _, r := range r.ComparisonItems {
-		r.Time = strings.ReplaceAll(r.Time, "+", " ")
+	return std().Explore(ctx, r, hl)
+}
+
+// Explore is like the package-level Explore, using the client c.
+func (c *Client) Explore(ctx context.Context, r *ExploreRequest, hl string) (ExploreResponse, error) {
+	if c.strict {
+		if err := c.ValidateExploreCombo(r.Category, r.Property); err != nil {
+			return nil, err
+		}
 	}
 
+	u, err := c.exploreURL(r, hl)
+	if err != nil {
+		return nil, err
+	}
+
+	key := hl + " " + r.cacheKey(c.normalizeKeywords)
+	str, cached := "", false
+	if c.explores != nil {
+		str, cached = c.explores.get(key)
+	}
+
+	if !cached {
+		b, err := c.do(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+
+		// google api returns not valid json :(
+		str = strings.Replace(string(b), ")]}'", "", 1)
+	}
+
+	out := new(exploreOut)
+	if err := c.unmarshal(str, out); err != nil {
+		return nil, err
+	}
+
+	if c.explores != nil && !cached {
+		c.explores.set(key, str)
+	}
+
+	widgets := ExploreResponse(out.Widgets).Deduplicate()
+	if c.skipEmptyWidgets {
+		widgets = widgets.withRequests()
+	}
+
+	return widgets, nil
+}
+
+// exploreURL builds the URL Explore sends the explore request to. The request is
+// normalized on a copy, so r and its comparison items are left unmodified.
+func (c *gClient) exploreURL(r *ExploreRequest, hl string) (*url.URL, error) {
 	u, _ := url.Parse(gAPI + gSExplore)
 
 	p := make(url.Values)
-	p.Set(paramTZ, "0")
+	p.Set(paramTZ, strconv.Itoa(c.tz))
 	p.Set(paramHl, hl)
 
 	// marshal request for query param
-	reqBytes, err := json.Marshal(r)
+	reqBytes, err := json.Marshal(r.normalized(c.normalizeKeywords))
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", errInvalidRequest, err)
 	}
@@ -199,20 +408,21 @@ func Explore(ctx context.Context, r *ExploreRequest, hl string) (ExploreResponse
 	p.Set(paramReq, mReq)
 	u.RawQuery = p.Encode()
 
-	b, err := client.do(ctx, u)
-	if err != nil {
-		return nil, err
-	}
+	return u, nil
+}
 
-	// google api returns not valid json :(
-	str := strings.Replace(string(b), ")]}'", "", 1)
+// widgetURL builds the URL of a widget data endpoint for a marshaled widget request.
+func (c *gClient) widgetURL(path, token, hl string, reqBytes []byte) *url.URL {
+	u, _ := url.Parse(gAPI + path)
 
-	out := new(exploreOut)
-	if err := client.unmarshal(str, out); err != nil {
-		return nil, err
-	}
+	p := make(url.Values)
+	p.Set(paramTZ, strconv.Itoa(c.tz))
+	p.Set(paramHl, hl)
+	p.Set(paramToken, token)
+	p.Set(paramReq, string(reqBytes))
+	u.RawQuery = p.Encode()
 
-	return out.Widgets, nil
+	return u
 }
 
 // InterestOverTime retrieves timeline data showing interest levels over the specified time period.
@@ -223,12 +433,25 @@ func Explore(ctx context.Context, r *ExploreRequest, hl string) (ExploreResponse
 //   - Interest values (0-100) for each compared keyword
 //   - Formatted values for display
 //
+// For the "all" time range (2004 to present) the request asks for MONTH resolution,
+// the only one Google serves for that range. For custom "YYYY-MM-DD YYYY-MM-DD"
+// ranges the resolution is derived from the range length (see RangeResolution).
+// Either way the resolution used is left in w.Request.Resolution.
+//
+// For multi-keyword comparisons the request uses the PERCENTAGES data mode, the
+// same as InterestByLocation. All Value entries of a point are then scaled together,
+// with 100 being the peak of the most popular keyword, so values are comparable
+// across keywords and consistent with the geographic comparison.
+//
 // Parameters:
 //   - ctx: Context for request cancellation and timeouts
 //   - w: An ExploreWidget of type TIMESERIES (obtained from Explore)
 //   - hl: Host language code (e.g., "EN", "RU")
 //
 // Returns ErrInvalidWidgetType if the widget is not a TIMESERIES type.
+// Returns ErrMissingToken if the widget has no valid token.
+// Returns ErrMissingRequest if the widget has no request payload.
+// Returns ErrTokenExpired if the widget token has expired; re-run Explore to get a fresh one.
 //
 // Example:
 //
@@ -242,16 +465,122 @@ func Explore(ctx context.Context, r *ExploreRequest, hl string) (ExploreResponse
 //	    fmt.Printf("%s: %d\n", point.FormattedTime, point.Value[0])
 //	}
 func InterestOverTime(ctx context.Context, w *ExploreWidget, hl string) ([]*Timeline, error) {
+	return std().InterestOverTime(ctx, w, hl)
+}
+
+// InterestOverTime is like the package-level InterestOverTime, using the client c.
+func (c *Client) InterestOverTime(ctx context.Context, w *ExploreWidget, hl string) ([]*Timeline, error) {
+	out, err := c.interestOverTime(ctx, w, hl)
+	if err != nil {
+		return nil, err
+	}
+
+	return out.TimelineData, nil
+}
+
+// interestOverTime validates a TIMESERIES widget and fetches its multiline data.
+func (c *Client) interestOverTime(ctx context.Context, w *ExploreWidget, hl string) (*multiline, error) {
 	if !strings.HasPrefix(w.ID, string(IntOverTimeWidgetID)) {
 		return nil, ErrInvalidWidgetType
 	}
 
-	u, _ := url.Parse(gAPI + gSIntOverTime)
+	if !w.HasValidToken() {
+		return nil, ErrMissingToken
+	}
 
-	p := make(url.Values)
-	p.Set(paramTZ, "0")
-	p.Set(paramHl, hl)
-	p.Set(paramToken, w.Token)
+	reqBytes, err := timelineRequest(w)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.fetchMultiline(ctx, w.Token, hl, reqBytes)
+}
+
+// InterestOverTimeNormalized retrieves timeline data like InterestOverTime and
+// returns it together with its per-keyword min-max scaled form, see NormalizeTimeline.
+// Points without data are NaN in the scaled matrix.
+//
+// Example:
+//
+//	timeline, scaled, err := googletrends.InterestOverTimeNormalized(ctx, timeWidgets[0], "EN")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(timeline[0].FormattedTime, scaled[0])
+func InterestOverTimeNormalized(ctx context.Context, w *ExploreWidget, hl string) ([]*Timeline, [][]float64, error) {
+	return std().InterestOverTimeNormalized(ctx, w, hl)
+}
+
+// InterestOverTimeNormalized is like the package-level InterestOverTimeNormalized, using the client c.
+func (c *Client) InterestOverTimeNormalized(ctx context.Context, w *ExploreWidget, hl string) ([]*Timeline, [][]float64, error) {
+	data, err := c.InterestOverTime(ctx, w, hl)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, NormalizeTimeline(data), nil
+}
+
+// InterestOverTimeWithAverages retrieves timeline data like InterestOverTime together
+// with the overall average of every keyword over the whole range, the numbers the web
+// UI shows next to the chart. averages has one value per keyword in comparison order,
+// and is empty when Google sends none.
+//
+// Example:
+//
+//	timeline, averages, err := googletrends.InterestOverTimeWithAverages(ctx, timeWidgets[0], "EN")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for i, item := range request.ComparisonItems {
+//	    fmt.Println(item.Keyword, averages[i])
+//	}
+func InterestOverTimeWithAverages(ctx context.Context, w *ExploreWidget, hl string) ([]*Timeline, []int, error) {
+	return std().InterestOverTimeWithAverages(ctx, w, hl)
+}
+
+// InterestOverTimeWithAverages is like the package-level InterestOverTimeWithAverages, using the client c.
+func (c *Client) InterestOverTimeWithAverages(ctx context.Context, w *ExploreWidget, hl string) ([]*Timeline, []int, error) {
+	out, err := c.interestOverTime(ctx, w, hl)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	averages := out.Averages
+	if averages == nil {
+		averages = []int{}
+	}
+
+	return out.TimelineData, averages, nil
+}
+
+// InterestOverTimeFilled retrieves timeline data like InterestOverTime and fills the
+// values without data with FillTimelineGaps, for charting libraries that dislike gaps.
+// Filled values are marked in the Filled slice of their point.
+//
+// Example:
+//
+//	timeline, err := googletrends.InterestOverTimeFilled(ctx, timeWidgets[0], "EN")
+func InterestOverTimeFilled(ctx context.Context, w *ExploreWidget, hl string) ([]*Timeline, error) {
+	return std().InterestOverTimeFilled(ctx, w, hl)
+}
+
+// InterestOverTimeFilled is like the package-level InterestOverTimeFilled, using the client c.
+func (c *Client) InterestOverTimeFilled(ctx context.Context, w *ExploreWidget, hl string) ([]*Timeline, error) {
+	data, err := c.InterestOverTime(ctx, w, hl)
+	if err != nil {
+		return nil, err
+	}
+
+	return FillTimelineGaps(data), nil
+}
+
+// timelineRequest prepares the widget request of a TIMESERIES widget
+// and marshals it for the req query param.
+func timelineRequest(w *ExploreWidget) ([]byte, error) {
+	if w.Request == nil {
+		return nil, ErrMissingRequest
+	}
 
 	// Initialize empty Geo maps where needed
 	for i, v := range w.Request.CompItem {
@@ -260,17 +589,59 @@ func InterestOverTime(ctx context.Context, w *ExploreWidget, hl string) ([]*Time
 		}
 	}
 
+	if len(w.Request.CompItem) > 1 {
+		w.Request.DataMode = compareDataMode
+	}
+
+	// custom ranges get the resolution google picks for their length
+	if res, ok := RangeResolution(w.Request.Time); ok {
+		w.Request.Resolution = res
+	}
+	for _, v := range w.Request.CompItem {
+		if v == nil {
+			continue
+		}
+		if res, ok := RangeResolution(v.Time); ok {
+			w.Request.Resolution = res
+		}
+	}
+
+	// "all" covers 2004 to present and is only served at monthly resolution;
+	// explore widgets carry it as the original range of their resolved one
+	if w.Request.Time == timeAll {
+		w.Request.Resolution = resolutionMonth
+	}
+	for _, v := range w.Request.CompItem {
+		if v != nil && (v.Time == timeAll || v.OriginalTimeRangeForExploreURL == timeAll) {
+			w.Request.Resolution = resolutionMonth
+		}
+	}
+
 	// marshal request for query param
 	reqBytes, err := json.Marshal(w.Request)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", errInvalidRequest, err)
 	}
-	mReq := string(reqBytes)
 
-	p.Set(paramReq, mReq)
-	u.RawQuery = p.Encode()
+	return reqBytes, nil
+}
 
-	b, err := client.do(ctx, u)
+// fetchTimeline requests and parses interest over time data for a marshaled widget request.
+func (c *gClient) fetchTimeline(ctx context.Context, token, hl string, reqBytes []byte) ([]*Timeline, error) {
+	out, err := c.fetchMultiline(ctx, token, hl, reqBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return out.TimelineData, nil
+}
+
+// fetchMultiline requests and parses the multiline payload of a marshaled widget request,
+// holding the timeline data and the keyword averages.
+func (c *gClient) fetchMultiline(ctx context.Context, token, hl string, reqBytes []byte) (*multiline, error) {
+	u := c.widgetURL(gSIntOverTime, token, hl, reqBytes)
+
+	b, err := c.do(ctx, u)
 	if err != nil {
 		return nil, err
 	}
@@ -279,11 +650,37 @@ func InterestOverTime(ctx context.Context, w *ExploreWidget, hl string) ([]*Time
 	str := strings.Replace(string(b), ")]}',", "", 1)
 
 	out := new(multilineOut)
-	if err := client.unmarshal(str, out); err != nil {
+	if err := c.unmarshal(str, out); err != nil {
 		return nil, err
 	}
 
-	return out.Default.TimelineData, nil
+	// google returns an empty payload instead of an error for expired tokens
+	if out.Default == nil {
+		return nil, ErrTokenExpired
+	}
+
+	return out.Default, nil
+}
+
+// GeoOption is a functional option for configuring a single InterestByLocation call.
+// Options are applied to a copy of the widget request, so the widget itself
+// is left untouched and can be reused with different options.
+type GeoOption func(*WidgetResponse)
+
+// WithDMA returns a GeoOption that breaks US interest down by Nielsen designated
+// market areas (media markets) instead of states. DMAs exist only in the US, so the
+// widget must be restricted to the US; InterestByLocation returns an error otherwise.
+//
+// DMA regions carry numeric codes such as "807"; use GeoMap.DisplayName or GeoName
+// for labels like "San Francisco-Oakland-San Jose CA".
+//
+// Example:
+//
+//	markets, err := googletrends.InterestByLocation(ctx, geoWidget, "EN", googletrends.WithDMA())
+func WithDMA() GeoOption {
+	return func(r *WidgetResponse) {
+		r.Resolution = resolutionDMA
+	}
 }
 
 // InterestByLocation retrieves geographic distribution data showing interest by region.
@@ -298,8 +695,18 @@ func InterestOverTime(ctx context.Context, w *ExploreWidget, hl string) ([]*Time
 //   - ctx: Context for request cancellation and timeouts
 //   - w: An ExploreWidget of type GEO_MAP (obtained from Explore)
 //   - hl: Host language code (e.g., "EN", "RU")
+//   - opts: Optional call options such as WithDMA
+//
+// Regions without data for a keyword are kept with a zero value and HasData false;
+// use GeoMaps.FilterHasData to omit them.
+//
+// Worldwide widgets (without a geo restriction) are always requested
+// with COUNTRY resolution, so regions are countries coded like "US" or "GB".
 //
 // Returns ErrInvalidWidgetType if the widget is not a GEO_MAP type.
+// Returns ErrMissingToken if the widget has no valid token.
+// Returns ErrMissingRequest if the widget has no request payload.
+// Returns ErrTokenExpired if the widget token has expired; re-run Explore to get a fresh one.
 //
 // Example:
 //
@@ -312,32 +719,88 @@ func InterestOverTime(ctx context.Context, w *ExploreWidget, hl string) ([]*Time
 //	for _, region := range regions {
 //	    fmt.Printf("%s (%s): %d\n", region.GeoName, region.GeoCode, region.Value[0])
 //	}
-func InterestByLocation(ctx context.Context, w *ExploreWidget, hl string) ([]*GeoMap, error) {
+func InterestByLocation(ctx context.Context, w *ExploreWidget, hl string, opts ...GeoOption) ([]*GeoMap, error) {
+	return std().InterestByLocation(ctx, w, hl, opts...)
+}
+
+// InterestByLocation is like the package-level InterestByLocation, using the client c.
+func (c *Client) InterestByLocation(ctx context.Context, w *ExploreWidget, hl string, opts ...GeoOption) ([]*GeoMap, error) {
 	if !strings.HasPrefix(w.ID, string(IntOverRegionID)) {
 		return nil, ErrInvalidWidgetType
 	}
 
-	u, _ := url.Parse(gAPI + gSIntOverReg)
+	if !w.HasValidToken() {
+		return nil, ErrMissingToken
+	}
 
-	p := make(url.Values)
-	p.Set(paramTZ, "0")
-	p.Set(paramHl, hl)
-	p.Set(paramToken, w.Token)
+	reqBytes, err := geoRequest(w, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.fetchGeo(ctx, w.Token, hl, reqBytes)
+}
+
+// geoRequest prepares the widget request of a GEO_MAP widget
+// and marshals it for the req query param.
+func geoRequest(w *ExploreWidget, opts ...GeoOption) ([]byte, error) {
+	if w.Request == nil {
+		return nil, ErrMissingRequest
+	}
 
 	if len(w.Request.CompItem) > 1 {
 		w.Request.DataMode = compareDataMode
 	}
 
+	// apply call options to a copy to keep the widget reusable
+	req := *w.Request
+
+	// worldwide maps are only meaningful per country,
+	// whatever resolution google defaulted the widget to
+	if requestCountry(&req) == "" {
+		req.Resolution = resolutionCountry
+	}
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	if req.Resolution == resolutionDMA {
+		if country := requestCountry(&req); country != locationUS {
+			return nil, fmt.Errorf("%s: DMA resolution is available for the US only, got geo %q", errInvalidRequest, country)
+		}
+	}
+
 	// marshal request for query param
-	reqBytes, err := json.Marshal(w.Request)
+	reqBytes, err := json.Marshal(&req)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", errInvalidRequest, err)
 	}
 
-	p.Set(paramReq, string(reqBytes))
-	u.RawQuery = p.Encode()
+	return reqBytes, nil
+}
+
+// requestCountry returns the country a widget request is restricted to,
+// or an empty string for worldwide requests.
+func requestCountry(r *WidgetResponse) string {
+	switch geo := r.Geo.(type) {
+	case string:
+		return geo
+	case map[string]string:
+		return geo["country"]
+	case map[string]interface{}:
+		country, _ := geo["country"].(string)
+		return country
+	}
+
+	return ""
+}
 
-	b, err := client.do(ctx, u)
+// fetchGeo requests and parses interest by location data for a marshaled widget request.
+func (c *gClient) fetchGeo(ctx context.Context, token, hl string, reqBytes []byte) ([]*GeoMap, error) {
+	u := c.widgetURL(gSIntOverReg, token, hl, reqBytes)
+
+	b, err := c.do(ctx, u)
 	if err != nil {
 		return nil, err
 	}
@@ -346,13 +809,36 @@ func InterestByLocation(ctx context.Context, w *ExploreWidget, hl string) ([]*Ge
 	str := strings.Replace(string(b), ")]}',", "", 1)
 
 	out := new(geoOut)
-	if err := client.unmarshal(str, out); err != nil {
+	if err := c.unmarshal(str, out); err != nil {
 		return nil, err
 	}
 
+	// google returns an empty payload instead of an error for expired tokens
+	if out.Default == nil {
+		return nil, ErrTokenExpired
+	}
+
 	return out.Default.GeoMapData, nil
 }
 
+// RelatedOption is a functional option for configuring a single Related call.
+// Options are applied to a copy of the widget request, so the widget itself
+// is left untouched and can be reused with different options.
+type RelatedOption func(*WidgetResponse)
+
+// WithTopOnly returns a RelatedOption that requests only the TOP ranking,
+// skipping the RISING one. This reduces the response size when rising
+// queries or topics are not needed.
+//
+// Example:
+//
+//	top, err := googletrends.Related(ctx, widget, "EN", googletrends.WithTopOnly())
+func WithTopOnly() RelatedOption {
+	return func(r *WidgetResponse) {
+		r.Metric = []string{metricTop}
+	}
+}
+
 // Related retrieves related topics or queries for a keyword.
 // The function supports both RELATED_QUERIES and RELATED_TOPICS widget types.
 //
@@ -365,8 +851,13 @@ func InterestByLocation(ctx context.Context, w *ExploreWidget, hl string) ([]*Ge
 //   - ctx: Context for request cancellation and timeouts
 //   - w: An ExploreWidget of type RELATED_QUERIES or RELATED_TOPICS (obtained from Explore)
 //   - hl: Host language code (e.g., "EN", "RU")
+//   - opts: Optional RelatedOption values (e.g., WithTopOnly); by default both
+//     TOP and RISING rankings are requested as returned by Explore
 //
 // Returns ErrInvalidWidgetType if the widget is not a RELATED_QUERIES or RELATED_TOPICS type.
+// Returns ErrMissingToken if the widget has no valid token.
+// Returns ErrMissingRequest if the widget has no request payload.
+// Returns ErrTokenExpired if the widget token has expired; re-run Explore to get a fresh one.
 //
 // Example:
 //
@@ -385,32 +876,79 @@ func InterestByLocation(ctx context.Context, w *ExploreWidget, hl string) ([]*Ge
 //	for _, t := range topics {
 //	    fmt.Printf("%s (%s): %s\n", t.Topic.Title, t.Topic.Type, t.FormattedValue)
 //	}
-func Related(ctx context.Context, w *ExploreWidget, hl string) ([]*RankedKeyword, error) {
+func Related(ctx context.Context, w *ExploreWidget, hl string, opts ...RelatedOption) ([]*RankedKeyword, error) {
+	return std().Related(ctx, w, hl, opts...)
+}
+
+// Related is like the package-level Related, using the client c.
+func (c *Client) Related(ctx context.Context, w *ExploreWidget, hl string, opts ...RelatedOption) ([]*RankedKeyword, error) {
 	if !strings.HasPrefix(w.ID, string(RelatedQueriesID)) && !strings.HasPrefix(w.ID, string(RelatedTopicsID)) {
 		return nil, ErrInvalidWidgetType
 	}
 
-	u, _ := url.Parse(gAPI + gSRelated)
+	if !w.HasValidToken() {
+		return nil, ErrMissingToken
+	}
 
-	p := make(url.Values)
-	p.Set(paramTZ, "0")
-	p.Set(paramHl, hl)
-	p.Set(paramToken, w.Token)
+	reqBytes, metrics, err := relatedRequest(w, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.fetchRelated(ctx, w.Token, hl, reqBytes, metrics)
+}
+
+// relatedRequest prepares the widget request of a RELATED_QUERIES or RELATED_TOPICS
+// widget, applies the call options and marshals it for the req query param.
+// It also returns the requested metrics, in the order of the ranked lists.
+func relatedRequest(w *ExploreWidget, opts ...RelatedOption) ([]byte, []string, error) {
+	if w.Request == nil {
+		return nil, nil, ErrMissingRequest
+	}
 
 	if len(w.Request.Restriction.Geo) == 0 {
 		w.Request.Restriction.Geo[""] = ""
 	}
 
+	// apply call options to a copy to keep the widget reusable
+	req := *w.Request
+	for _, opt := range opts {
+		opt(&req)
+	}
+
 	// marshal request for query param
-	reqBytes, err := json.Marshal(w.Request)
+	reqBytes, err := json.Marshal(&req)
 	if err != nil {
-		return nil, fmt.Errorf("%s: %w", errInvalidRequest, err)
+		return nil, nil, fmt.Errorf("%s: %w", errInvalidRequest, err)
 	}
 
-	p.Set(paramReq, string(reqBytes))
-	u.RawQuery = p.Encode()
+	return reqBytes, req.Metric, nil
+}
+
+// fetchRelated requests and parses related searches data for a marshaled widget request
+// asking for metrics.
+func (c *gClient) fetchRelated(ctx context.Context, token, hl string, reqBytes []byte, metrics []string) ([]*RankedKeyword, error) {
+	lists, err := c.fetchRankedLists(ctx, token, hl, reqBytes, metrics)
+	if err != nil {
+		return nil, err
+	}
+
+	// split all keywords together
+	keywords := make([]*RankedKeyword, 0)
+	for _, v := range lists {
+		keywords = append(keywords, v.Keywords...)
+	}
+
+	return keywords, nil
+}
 
-	b, err := client.do(ctx, u)
+// fetchRankedLists requests related searches data for a marshaled widget request
+// and returns its ranked lists, one per requested metric. Keywords of the RISING
+// list, as told by metrics, are marked rising.
+func (c *gClient) fetchRankedLists(ctx context.Context, token, hl string, reqBytes []byte, metrics []string) ([]*rankedList, error) {
+	u := c.widgetURL(gSRelated, token, hl, reqBytes)
+
+	b, err := c.do(ctx, u)
 	if err != nil {
 		return nil, err
 	}
@@ -419,17 +957,61 @@ func Related(ctx context.Context, w *ExploreWidget, hl string) ([]*RankedKeyword
 	str := strings.Replace(string(b), ")]}',", "", 1)
 
 	out := new(relatedOut)
-	if err := client.unmarshal(str, out); err != nil {
+	if err := c.unmarshal(str, out); err != nil {
 		return nil, err
 	}
 
-	// split all keywords together
-	keywords := make([]*RankedKeyword, 0)
-	for _, v := range out.Default.Ranked {
-		keywords = append(keywords, v.Keywords...)
+	// google returns an empty payload instead of an error for expired tokens
+	if out.Default == nil {
+		return nil, ErrTokenExpired
 	}
 
-	return keywords, nil
+	for i, list := range out.Default.Ranked {
+		if list == nil || rankedMetric(i, metrics) != metricRising {
+			continue
+		}
+
+		for _, k := range list.Keywords {
+			if k != nil {
+				k.rising = true
+			}
+		}
+	}
+
+	return out.Default.Ranked, nil
+}
+
+// SearchOption is a functional option for configuring a single Search call.
+type SearchOption func(url.Values)
+
+// WithSearchGeo returns a SearchOption that localizes autocomplete suggestions
+// to the given location code (e.g., "US", "DE"). The same word can suggest
+// different local entities per country. An empty geo keeps the worldwide suggestions.
+//
+// Example:
+//
+//	suggestions, err := googletrends.Search(ctx, "mercury", "EN", googletrends.WithSearchGeo("DE"))
+func WithSearchGeo(geo string) SearchOption {
+	return func(p url.Values) {
+		if geo = NormalizeGeo(geo); len(geo) != 0 {
+			p.Set(paramGeo, geo)
+		}
+	}
+}
+
+// WithSearchCategory returns a SearchOption that narrows autocomplete suggestions
+// to a category ID from ExploreCategories, e.g. "apple" in Computers & Electronics
+// rather than Food & Drink. The default category 0 (all categories) omits the parameter.
+//
+// Example:
+//
+//	suggestions, err := googletrends.Search(ctx, "apple", "EN", googletrends.WithSearchCategory(5))
+func WithSearchCategory(category int) SearchOption {
+	return func(p url.Values) {
+		if category != 0 {
+			p.Set(paramCat, strconv.Itoa(category))
+		}
+	}
 }
 
 // Search provides autocomplete suggestions for a keyword query.
@@ -445,6 +1027,7 @@ func Related(ctx context.Context, w *ExploreWidget, hl string) ([]*RankedKeyword
 //   - ctx: Context for request cancellation and timeouts
 //   - word: The search term to get suggestions for
 //   - hl: Host language code (e.g., "EN", "RU")
+//   - opts: Optional call options such as WithSearchGeo and WithSearchCategory
 //
 // Example:
 //
@@ -458,17 +1041,25 @@ func Related(ctx context.Context, w *ExploreWidget, hl string) ([]*RankedKeyword
 //	// Output might include:
 //	// Python (Programming language) - MID: /m/05z1_
 //	// Python (Snake) - MID: /m/06blk
-func Search(ctx context.Context, word, hl string) ([]*KeywordTopic, error) {
+func Search(ctx context.Context, word, hl string, opts ...SearchOption) ([]*KeywordTopic, error) {
+	return std().Search(ctx, word, hl, opts...)
+}
+
+// Search is like the package-level Search, using the client c.
+func (c *Client) Search(ctx context.Context, word, hl string, opts ...SearchOption) ([]*KeywordTopic, error) {
 	req := fmt.Sprintf("%s%s/%s", gAPI, gSAutocomplete, url.QueryEscape(word))
 	u, _ := url.Parse(req)
 
 	p := make(url.Values)
-	p.Set(paramTZ, "0")
+	p.Set(paramTZ, strconv.Itoa(c.tz))
 	p.Set(paramHl, hl)
+	for _, opt := range opts {
+		opt(p)
+	}
 
 	u.RawQuery = p.Encode()
 
-	b, err := client.do(ctx, u)
+	b, err := c.do(ctx, u)
 	if err != nil {
 		return nil, err
 	}
@@ -477,7 +1068,7 @@ func Search(ctx context.Context, word, hl string) ([]*KeywordTopic, error) {
 	str := strings.Replace(string(b), ")]}',", "", 1)
 
 	out := new(searchOut)
-	if err := client.unmarshal(str, out); err != nil {
+	if err := c.unmarshal(str, out); err != nil {
 		return nil, err
 	}
 
@@ -488,6 +1079,161 @@ func Search(ctx context.Context, word, hl string) ([]*KeywordTopic, error) {
 	return keywords, nil
 }
 
+// ExploreTopics compares Knowledge Graph topics (e.g., from Search) in a single Explore call.
+// Each topic becomes a ComparisonItem keyed by its Mid, which gives more precise results
+// than comparing plain keywords. Topics without a Mid fall back to their Title.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - topics: From 1 to 5 topics to compare
+//   - geo: Geographic location code (e.g., "US"), empty for worldwide
+//   - timeRange: Time range for all items (e.g., "today 12-m")
+//   - category: Category ID filter, 0 for all categories
+//   - hl: Host language code (e.g., "EN", "RU")
+//
+// Returns an error if no topics or more than 5 topics are passed.
+//
+// Example:
+//
+//	golang, _ := googletrends.Search(ctx, "golang", "EN")
+//	python, _ := googletrends.Search(ctx, "python", "EN")
+//	widgets, err := googletrends.ExploreTopics(ctx, []*googletrends.KeywordTopic{golang[0], python[0]},
+//	    "US", "today 12-m", 0, "EN")
+func ExploreTopics(ctx context.Context, topics []*KeywordTopic, geo, timeRange string, category int, hl string) (ExploreResponse, error) {
+	return std().ExploreTopics(ctx, topics, geo, timeRange, category, hl)
+}
+
+// ExploreTopics is like the package-level ExploreTopics, using the client c.
+func (c *Client) ExploreTopics(ctx context.Context, topics []*KeywordTopic, geo, timeRange string, category int, hl string) (ExploreResponse, error) {
+	if len(topics) == 0 || len(topics) > maxComparisonItems {
+		return nil, fmt.Errorf("%s: topics count must be between 1 and %d, got %d",
+			errInvalidRequest, maxComparisonItems, len(topics))
+	}
+
+	items := make([]*ComparisonItem, 0, len(topics))
+	for i, t := range topics {
+		if t == nil {
+			return nil, fmt.Errorf("%s: topic %d is nil", errInvalidRequest, i)
+		}
+
+		keyword := t.Mid
+		if len(keyword) == 0 {
+			keyword = t.Title
+		}
+
+		items = append(items, &ComparisonItem{
+			Keyword: keyword,
+			Geo:     geo,
+			Time:    timeRange,
+		})
+	}
+
+	return c.Explore(ctx, &ExploreRequest{
+		ComparisonItems: items,
+		Category:        category,
+	}, hl)
+}
+
+// ResolveEntity returns the Knowledge Graph topic that best matches keyword among
+// its Search suggestions, for comparing entities rather than plain queries.
+// The best match is the first suggestion with a Mid whose Title equals the keyword
+// (ignoring case and surrounding spaces), or else the first suggestion with a Mid.
+// It returns nil without an error when no suggestion is an entity.
+//
+// Example:
+//
+//	topic, err := googletrends.ResolveEntity(ctx, "golang", "EN")
+//	if err == nil && topic != nil {
+//	    fmt.Println(topic.Mid, topic.Type)
+//	}
+func ResolveEntity(ctx context.Context, keyword, hl string, opts ...SearchOption) (*KeywordTopic, error) {
+	return std().ResolveEntity(ctx, keyword, hl, opts...)
+}
+
+// ResolveEntity is like the package-level ResolveEntity, using the client c.
+func (c *Client) ResolveEntity(ctx context.Context, keyword, hl string, opts ...SearchOption) (*KeywordTopic, error) {
+	topics, err := c.Search(ctx, keyword, hl, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return bestEntity(keyword, topics), nil
+}
+
+// bestEntity picks the best entity match for keyword among topics, see ResolveEntity.
+func bestEntity(keyword string, topics []*KeywordTopic) *KeywordTopic {
+	keyword = strings.TrimSpace(keyword)
+
+	var first *KeywordTopic
+	for _, t := range topics {
+		if t == nil || len(t.Mid) == 0 {
+			continue
+		}
+
+		if strings.EqualFold(strings.TrimSpace(t.Title), keyword) {
+			return t
+		}
+		if first == nil {
+			first = t
+		}
+	}
+
+	return first
+}
+
+// ResolveEntities resolves many keywords to Knowledge Graph topics with ResolveEntity,
+// running at most a few requests at a time. The result maps every resolved keyword to
+// its best entity match, or to nil when no suggestion is an entity. Repeated keywords
+// are looked up once.
+//
+// All keywords are looked up even if some fail. The returned error joins the errors of
+// the failed keywords, which are left out of the map.
+//
+// Example:
+//
+//	topics, err := googletrends.ResolveEntities(ctx, []string{"golang", "rust", "zig"}, "EN")
+//	if err != nil {
+//	    log.Println(err)
+//	}
+//	for keyword, topic := range topics {
+//	    if topic != nil {
+//	        fmt.Println(keyword, topic.Mid)
+//	    }
+//	}
+func ResolveEntities(ctx context.Context, keywords []string, hl string, opts ...SearchOption) (map[string]*KeywordTopic, error) {
+	return std().ResolveEntities(ctx, keywords, hl, opts...)
+}
+
+// ResolveEntities is like the package-level ResolveEntities, using the client c.
+func (c *Client) ResolveEntities(ctx context.Context, keywords []string, hl string, opts ...SearchOption) (map[string]*KeywordTopic, error) {
+	unique := make([]string, 0, len(keywords))
+	seen := make(map[string]struct{}, len(keywords))
+	for _, k := range keywords {
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		unique = append(unique, k)
+	}
+
+	out := make(map[string]*KeywordTopic, len(unique))
+	mu := new(sync.Mutex)
+	err := forEachConcurrent(ctx, len(unique), maxConcurrentRequests, func(ctx context.Context, i int) error {
+		topic, err := c.ResolveEntity(ctx, unique[i], hl, opts...)
+		if err != nil {
+			return fmt.Errorf("%q: %w", unique[i], err)
+		}
+
+		mu.Lock()
+		out[unique[i]] = topic
+		mu.Unlock()
+
+		return nil
+	})
+
+	return out, err
+}
+
 // DailyNew retrieves daily trending searches using the new Google Trends batch execute API.
 // This is the recommended method for fetching daily trends as it uses a more stable API endpoint.
 //
@@ -501,8 +1247,9 @@ func Search(ctx context.Context, word, hl string) ([]*KeywordTopic, error) {
 //
 // Returns a slice of TrendingSearch items or an error if the request fails.
 //
-// Note: The new API returns only the search query; fields like FormattedTraffic,
-// Image, and Articles will be empty.
+// Note: The new API returns the search query and its news articles; FormattedTraffic
+// is empty. Image is taken from the first article with a picture and is nil for
+// trends without any.
 //
 // Example:
 //
@@ -515,21 +1262,40 @@ func Search(ctx context.Context, word, hl string) ([]*KeywordTopic, error) {
 //	    fmt.Println(trend.Title.Query)
 //	}
 func DailyNew(ctx context.Context, hl, loc string) ([]*TrendingSearch, error) {
-	terms, err := client.trendsNew(ctx, hl, loc)
+	return std().DailyNew(ctx, hl, loc)
+}
+
+// DailyNew is like the package-level DailyNew, using the client c.
+func (c *Client) DailyNew(ctx context.Context, hl, loc string) ([]*TrendingSearch, error) {
+	return c.trendsNew(ctx, hl, loc, 0)
+}
+
+// DailyTop retrieves the first n daily trending searches, like DailyNew.
+// It returns fewer than n trends when Google reports fewer, so the result
+// can be ranged over without bounds checks.
+//
+// Returns an error if n is not positive.
+//
+// Example:
+//
+//	top10, err := googletrends.DailyTop(ctx, "EN", "US", 10)
+func DailyTop(ctx context.Context, hl, loc string, n int) ([]*TrendingSearch, error) {
+	return std().DailyTop(ctx, hl, loc, n)
+}
+
+// DailyTop is like the package-level DailyTop, using the client c.
+func (c *Client) DailyTop(ctx context.Context, hl, loc string, n int) ([]*TrendingSearch, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("%s: n must be positive, got %d", errInvalidRequest, n)
+	}
+
+	searches, err := c.DailyNew(ctx, hl, loc)
 	if err != nil {
 		return nil, err
 	}
 
-	searches := make([]*TrendingSearch, 0, len(terms))
-	for _, term := range terms {
-		searches = append(searches, &TrendingSearch{
-			Title: &SearchTitle{
-				Query: term,
-			},
-			FormattedTraffic: "",
-			Image:            nil,
-			Articles:         []*SearchArticle{},
-		})
+	if len(searches) > n {
+		searches = searches[:n]
 	}
 
 	return searches, nil
@@ -565,26 +1331,401 @@ func DailyNew(ctx context.Context, hl, loc string) ([]*TrendingSearch, error) {
 //	    }
 //	}
 func DailyTrendingSearchNew(ctx context.Context, hl, loc string) ([]*TrendingSearchDays, error) {
-	terms, err := client.trendsNew(ctx, hl, loc)
+	return std().DailyTrendingSearchNew(ctx, hl, loc)
+}
+
+// DailyTrendingSearchNew is like the package-level DailyTrendingSearchNew, using the client c.
+func (c *Client) DailyTrendingSearchNew(ctx context.Context, hl, loc string) ([]*TrendingSearchDays, error) {
+	searches, err := c.trendsNew(ctx, hl, loc, 0)
 	if err != nil {
 		return nil, err
 	}
 
 	today := &TrendingSearchDays{
 		FormattedDate: "Today",
-		Searches:      make([]*TrendingSearch, 0, len(terms)),
+		Searches:      searches,
 	}
 
-	for _, term := range terms {
-		today.Searches = append(today.Searches, &TrendingSearch{
-			Title: &SearchTitle{
-				Query: term,
-			},
-			FormattedTraffic: "",
-			Image:            nil,
-			Articles:         []*SearchArticle{},
-		})
+	return []*TrendingSearchDays{today}, nil
+}
+
+// DailyDays retrieves daily trending searches of the past days grouped by day from the
+// legacy daily trends API, most recent day first. Unlike DailyTrendingSearchNew it
+// returns several days, each with its Date, and trends with FormattedTraffic,
+// images and articles including snippets.
+//
+// The legacy API is less reliable than the batch execute API behind DailyNew;
+// see DailyMerged for combining both.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - hl: Host language code (e.g., "EN", "RU") - affects result language
+//   - loc: Location code for regional trends (e.g., "US", "GB", "RU")
+//
+// Example:
+//
+//	days, err := googletrends.DailyDays(ctx, "EN", "US")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, day := range days {
+//	    fmt.Printf("=== %s (%d trends) ===\n", day.FormattedDate, len(day.Searches))
+//	}
+func DailyDays(ctx context.Context, hl, loc string) ([]*TrendingSearchDays, error) {
+	return std().DailyDays(ctx, hl, loc)
+}
+
+// DailyDays is like the package-level DailyDays, using the client c.
+func (c *Client) DailyDays(ctx context.Context, hl, loc string) ([]*TrendingSearchDays, error) {
+	return c.dailyLegacy(ctx, hl, loc, "")
+}
+
+// DailyOnDate retrieves the daily trending searches of a past day from the legacy daily
+// trends API, e.g. yesterday's or last week's trends, which the batch execute API behind
+// DailyNew doesn't provide. The calendar day of date is used in the location of date.
+//
+// Returns an error if date is in the future or older than the 30 days Google keeps.
+// An empty slice is returned if Google has no trends for that day.
+//
+// Example:
+//
+//	yesterday := time.Now().AddDate(0, 0, -1)
+//	trends, err := googletrends.DailyOnDate(ctx, "EN", "US", yesterday)
+func DailyOnDate(ctx context.Context, hl, loc string, date time.Time) ([]*TrendingSearch, error) {
+	return std().DailyOnDate(ctx, hl, loc, date)
+}
+
+// DailyOnDate is like the package-level DailyOnDate, using the client c.
+func (c *Client) DailyOnDate(ctx context.Context, hl, loc string, date time.Time) ([]*TrendingSearch, error) {
+	y, m, d := date.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, date.Location())
+	y, m, d = time.Now().In(date.Location()).Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, date.Location())
+
+	switch {
+	case day.After(today):
+		return nil, fmt.Errorf("%s: date %s is in the future", errInvalidRequest, day.Format(rangeDayLayout))
+	case day.Before(today.AddDate(0, 0, -dailyRetentionDays)):
+		return nil, fmt.Errorf("%s: date %s is older than %d days", errInvalidRequest, day.Format(rangeDayLayout), dailyRetentionDays)
 	}
 
-	return []*TrendingSearchDays{today}, nil
+	ed := day.Format(dailyDateLayout)
+	days, err := c.dailyLegacy(ctx, hl, loc, ed)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range days {
+		if v.Date == ed {
+			return v.Searches, nil
+		}
+	}
+
+	return []*TrendingSearch{}, nil
+}
+
+// DailyMultiLang retrieves daily trending searches for one location in several languages,
+// which is useful for multilingual regions like Switzerland. It runs one DailyNew request
+// per language code concurrently and returns the results keyed by language code.
+//
+// All languages are fetched even if some fail. The returned error joins the errors of
+// the failed languages, and the map still holds the results of the successful ones.
+//
+// Example:
+//
+//	byLang, err := googletrends.DailyMultiLang(ctx, []string{"DE", "FR", "IT"}, "CH")
+//	if err != nil {
+//	    log.Println(err)
+//	}
+//	for hl, trends := range byLang {
+//	    fmt.Println(hl, len(trends))
+//	}
+func DailyMultiLang(ctx context.Context, hls []string, loc string) (map[string][]*TrendingSearch, error) {
+	return std().DailyMultiLang(ctx, hls, loc)
+}
+
+// DailyMultiLang is like the package-level DailyMultiLang, using the client c.
+func (c *Client) DailyMultiLang(ctx context.Context, hls []string, loc string) (map[string][]*TrendingSearch, error) {
+	out := make(map[string][]*TrendingSearch, len(hls))
+	mu := new(sync.Mutex)
+	err := forEachConcurrent(ctx, len(hls), maxConcurrentRequests, func(ctx context.Context, i int) error {
+		hl := hls[i]
+		searches, err := c.DailyNew(ctx, hl, loc)
+		if err != nil {
+			return fmt.Errorf("%s: %w", hl, err)
+		}
+
+		mu.Lock()
+		out[hl] = searches
+		mu.Unlock()
+
+		return nil
+	})
+
+	return out, err
+}
+
+// DailyByCategories retrieves daily trending searches (as DailyNew) in loc for every
+// trending category id in categories concurrently, and returns the results keyed by
+// category id. Category 0 is all categories.
+//
+// All categories are fetched even if some fail. The returned error joins the errors of
+// the failed categories, and the map still holds the results of the successful ones.
+//
+// Example:
+//
+//	byCat, err := googletrends.DailyByCategories(ctx, "EN", "US", []int{17, 18})
+//	if err != nil {
+//	    log.Println(err)
+//	}
+//	for cat, trends := range byCat {
+//	    fmt.Println(cat, len(trends))
+//	}
+func DailyByCategories(ctx context.Context, hl, loc string, categories []int) (map[int][]*TrendingSearch, error) {
+	return std().DailyByCategories(ctx, hl, loc, categories)
+}
+
+// DailyByCategories is like the package-level DailyByCategories, using the client c.
+func (c *Client) DailyByCategories(ctx context.Context, hl, loc string, categories []int) (map[int][]*TrendingSearch, error) {
+	out := make(map[int][]*TrendingSearch, len(categories))
+	mu := new(sync.Mutex)
+	err := forEachConcurrent(ctx, len(categories), maxConcurrentRequests, func(ctx context.Context, i int) error {
+		cat := categories[i]
+		searches, err := c.trendsNew(ctx, hl, loc, cat)
+		if err != nil {
+			return fmt.Errorf("category %d: %w", cat, err)
+		}
+
+		mu.Lock()
+		out[cat] = searches
+		mu.Unlock()
+
+		return nil
+	})
+
+	return out, err
+}
+
+// DailyMultiStream retrieves daily trending searches (as DailyNew) for every location
+// code in locs concurrently, and sends each trend onto out tagged with its location
+// code, as passed in locs, as soon as the trends of that location are parsed, so they
+// can be processed as they arrive. Trends of one location keep their order; locations
+// are interleaved in completion order.
+//
+// DailyMultiStream blocks until all locations are done and closes out before
+// returning, so it is usually run in its own goroutine while the caller ranges over
+// out. When ctx is done, pending sends are abandoned and out is closed without
+// waiting for the reader.
+//
+// All locations are fetched even if some fail. The returned error joins the errors
+// of the failed locations.
+//
+// Example:
+//
+//	out := make(chan struct {
+//	    Loc    string
+//	    Search *googletrends.TrendingSearch
+//	})
+//	go func() {
+//	    if err := googletrends.DailyMultiStream(ctx, "EN", []string{"US", "GB", "DE"}, out); err != nil {
+//	        log.Println(err)
+//	    }
+//	}()
+//	for s := range out {
+//	    fmt.Println(s.Loc, s.Search.Title.Query)
+//	}
+func DailyMultiStream(ctx context.Context, hl string, locs []string, out chan<- struct {
+	Loc    string
+	Search *TrendingSearch
+}) error {
+	return std().DailyMultiStream(ctx, hl, locs, out)
+}
+
+// DailyMultiStream is like the package-level DailyMultiStream, using the client c.
+func (c *Client) DailyMultiStream(ctx context.Context, hl string, locs []string, out chan<- struct {
+	Loc    string
+	Search *TrendingSearch
+}) error {
+	defer close(out)
+
+	return forEachConcurrent(ctx, len(locs), maxConcurrentRequests, func(ctx context.Context, i int) error {
+		loc := locs[i]
+		searches, err := c.DailyNew(ctx, hl, loc)
+		if err != nil {
+			return fmt.Errorf("%s: %w", loc, err)
+		}
+
+		for _, s := range searches {
+			select {
+			case out <- struct {
+				Loc    string
+				Search *TrendingSearch
+			}{Loc: loc, Search: s}:
+			case <-ctx.Done():
+				return fmt.Errorf("%s: %w", loc, ctx.Err())
+			}
+		}
+
+		return nil
+	})
+}
+
+// DailyMerged retrieves daily trending searches from both the batch execute API
+// (as DailyNew) and the legacy daily trends API, concurrently, and combines them.
+// The batch execute API gives the list and order of trends; each trend is then
+// enriched with FormattedTraffic, Image, Articles and ShareURL from the legacy trend with
+// the same query (compared case-insensitively), filling only fields left empty.
+//
+// The legacy API is flakier. If it fails, the DailyNew results are returned
+// unchanged. If the batch execute API fails, the most recent day of the legacy
+// API is returned instead. An error is returned only when both sources fail.
+//
+// Example:
+//
+//	trends, err := googletrends.DailyMerged(ctx, "EN", "US")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, trend := range trends {
+//	    fmt.Println(trend.Title.Query, trend.FormattedTraffic)
+//	}
+func DailyMerged(ctx context.Context, hl, loc string) ([]*TrendingSearch, error) {
+	return std().DailyMerged(ctx, hl, loc)
+}
+
+// DailyMerged is like the package-level DailyMerged, using the client c.
+func (c *Client) DailyMerged(ctx context.Context, hl, loc string) ([]*TrendingSearch, error) {
+	var (
+		searches  []*TrendingSearch
+		days      []*TrendingSearchDays
+		newErr    error
+		legacyErr error
+	)
+
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		days, legacyErr = c.dailyLegacy(ctx, hl, loc, "")
+	}()
+	searches, newErr = c.trendsNew(ctx, hl, loc, 0)
+	wg.Wait()
+
+	switch {
+	case newErr != nil && legacyErr != nil:
+		return nil, errors.Join(newErr, legacyErr)
+	case newErr != nil:
+		if len(days) == 0 {
+			return []*TrendingSearch{}, nil
+		}
+		return days[0].Searches, nil
+	case legacyErr != nil:
+		return searches, nil
+	}
+
+	legacy := make(map[string]*TrendingSearch)
+	for _, day := range days {
+		for _, v := range day.Searches {
+			if v == nil || v.Title == nil {
+				continue
+			}
+
+			// days are ordered from the most recent, keep the latest entry of a query
+			key := strings.ToLower(v.Title.Query)
+			if _, ok := legacy[key]; !ok {
+				legacy[key] = v
+			}
+		}
+	}
+
+	for _, v := range searches {
+		if v.Title == nil {
+			continue
+		}
+
+		l, ok := legacy[strings.ToLower(v.Title.Query)]
+		if !ok {
+			continue
+		}
+
+		if len(v.FormattedTraffic) == 0 {
+			v.FormattedTraffic = l.FormattedTraffic
+		}
+		if v.Image == nil {
+			v.Image = l.Image
+		}
+		if len(v.Articles) == 0 {
+			v.Articles = l.Articles
+		}
+		if len(v.ShareURL) == 0 {
+			v.ShareURL = l.ShareURL
+		}
+	}
+
+	return searches, nil
+}
+
+// selfTestKeyword, selfTestGeo and selfTestTime define the known-good explore of SelfTest.
+const (
+	selfTestKeyword = "google"
+	selfTestGeo     = "US"
+	selfTestTime    = "today 12-m"
+)
+
+// SelfTest runs a minimal known-good flow against the live API, exploring "google"
+// in the US over "today 12-m" and fetching its interest over time, as a health
+// check probe. It returns nil only if timeline data comes back.
+//
+// The error names the stage that failed: "explore", "token" (no interest over time
+// widget with a valid token), "timeline" or "parse" (unparseable or empty data).
+// Underlying errors are wrapped, so errors.Is with ErrRequestFailed or
+// ErrConsentRequired points to network or access problems, while errors.As with
+// *ParseError points to Google changing the response format.
+//
+// Example:
+//
+//	if err := googletrends.SelfTest(ctx, "EN"); err != nil {
+//	    log.Printf("google trends unavailable: %v", err)
+//	}
+func SelfTest(ctx context.Context, hl string) error {
+	return std().SelfTest(ctx, hl)
+}
+
+// SelfTest is like the package-level SelfTest, using the client c.
+func (c *Client) SelfTest(ctx context.Context, hl string) error {
+	widgets, err := c.Explore(ctx, &ExploreRequest{
+		ComparisonItems: []*ComparisonItem{
+			{Keyword: selfTestKeyword, Geo: selfTestGeo, Time: selfTestTime},
+		},
+	}, hl)
+	if err != nil {
+		return selfTestError("explore", err)
+	}
+
+	timeWidgets := widgets.GetWidgetsByType(IntOverTimeWidgetID)
+	if len(timeWidgets) == 0 || !timeWidgets[0].HasValidToken() {
+		return fmt.Errorf("self test token: %w", ErrMissingToken)
+	}
+
+	timeline, err := c.InterestOverTime(ctx, timeWidgets[0], hl)
+	if err != nil {
+		return selfTestError("timeline", err)
+	}
+
+	if !Timelines(timeline).HasAnyData() {
+		return fmt.Errorf("self test parse: no timeline data for %q", selfTestKeyword)
+	}
+
+	return nil
+}
+
+// selfTestError wraps err with the SelfTest stage it happened in,
+// reporting parse errors as the parse stage.
+func selfTestError(stage string, err error) error {
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		stage = "parse"
+	}
+
+	return fmt.Errorf("self test %s: %w", stage, err)
 }
@@ -1,14 +1,23 @@
 package googletrends
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -22,7 +31,7 @@ const (
 
 func TestDebug(t *testing.T) {
 	Debug(true)
-	assert.True(t, client.debug)
+	assert.True(t, defaultClient().debug)
 	Debug(false)
 }
 
@@ -650,3 +659,1369 @@ func TestComparisonItemWithStartAndEndTime(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, len(overTime) > 0)
 }
+
+func TestRelatedTopOnly(t *testing.T) {
+	newWidget := func() *ExploreWidget {
+		return &ExploreWidget{
+			ID:    "RELATED_QUERIES_0",
+			Token: testToken,
+			Request: &WidgetResponse{
+				Restriction: WidgetComparisonItem{Geo: map[string]string{"country": locUS}},
+				Metric:      []string{metricTop, metricRising},
+			},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		opts           []RelatedOption
+		expectedMetric []string
+	}{
+		{
+			name:           "default keeps top and rising",
+			opts:           nil,
+			expectedMetric: []string{metricTop, metricRising},
+		},
+		{
+			name:           "top only",
+			opts:           []RelatedOption{WithTopOnly()},
+			expectedMetric: []string{metricTop},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent *WidgetResponse
+			useMockClient(t, func(req *http.Request) (*http.Response, error) {
+				sent = new(WidgetResponse)
+				if err := json.Unmarshal([]byte(req.URL.Query().Get(paramReq)), sent); err != nil {
+					return nil, err
+				}
+				return newMockResponse(http.StatusOK, `)]}',{"default":{"rankedList":[{"rankedKeyword":[{"query":"golang tutorial","value":100}]}]}}`), nil
+			})
+
+			w := newWidget()
+			keywords, err := Related(context.Background(), w, langEN, tt.opts...)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedMetric, sent.Metric)
+			assert.Equal(t, []string{metricTop, metricRising}, w.Request.Metric)
+			require.Len(t, keywords, 1)
+			assert.Equal(t, "golang tutorial", keywords[0].Query)
+		})
+	}
+}
+
+func TestInterestOverTimeDataMode(t *testing.T) {
+	tests := []struct {
+		name             string
+		compItems        []*WidgetComparisonItem
+		expectedDataMode string
+	}{
+		{
+			name:             "single keyword keeps default mode",
+			compItems:        []*WidgetComparisonItem{{Time: "today 12-m"}},
+			expectedDataMode: "",
+		},
+		{
+			name:             "multiple keywords use percentages",
+			compItems:        []*WidgetComparisonItem{{Time: "today 12-m"}, {Time: "today 12-m"}},
+			expectedDataMode: compareDataMode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent map[string]interface{}
+			useMockClient(t, func(req *http.Request) (*http.Response, error) {
+				if err := json.Unmarshal([]byte(req.URL.Query().Get(paramReq)), &sent); err != nil {
+					return nil, err
+				}
+				return newMockResponse(http.StatusOK, `)]}',{"default":{"timelineData":[{"time":"1609459200","value":[10,20]}]}}`), nil
+			})
+
+			w := &ExploreWidget{
+				ID:      string(IntOverTimeWidgetID),
+				Token:   testToken,
+				Request: &WidgetResponse{CompItem: tt.compItems},
+			}
+			timeline, err := InterestOverTime(context.Background(), w, langEN)
+			require.NoError(t, err)
+			require.Len(t, timeline, 1)
+
+			if tt.expectedDataMode == "" {
+				assert.NotContains(t, sent, "dataMode")
+			} else {
+				assert.Equal(t, tt.expectedDataMode, sent["dataMode"])
+			}
+		})
+	}
+}
+
+func TestExploreTopics(t *testing.T) {
+	t.Run("uses mids as keywords", func(t *testing.T) {
+		sent := new(ExploreRequest)
+		useMockClient(t, func(req *http.Request) (*http.Response, error) {
+			if err := json.Unmarshal([]byte(req.URL.Query().Get(paramReq)), sent); err != nil {
+				return nil, err
+			}
+			return newMockResponse(http.StatusOK, `)]}'{"widgets":[{"id":"TIMESERIES","token":"`+testToken+`"}]}`), nil
+		})
+
+		topics := []*KeywordTopic{
+			{Mid: "/m/09gbxjr", Title: "Go", Type: "Programming language"},
+			{Title: "Rust"},
+		}
+		widgets, err := ExploreTopics(context.Background(), topics, locUS, "today 12-m", catProgramming, langEN)
+
+		require.NoError(t, err)
+		assert.Len(t, widgets, 1)
+		assert.Equal(t, catProgramming, sent.Category)
+		require.Len(t, sent.ComparisonItems, 2)
+		assert.Equal(t, "/m/09gbxjr", sent.ComparisonItems[0].Keyword)
+		assert.Equal(t, "Rust", sent.ComparisonItems[1].Keyword)
+		for _, item := range sent.ComparisonItems {
+			assert.Equal(t, locUS, item.Geo)
+			assert.Equal(t, "today 12-m", item.Time)
+		}
+	})
+
+	t.Run("rejects invalid topic counts", func(t *testing.T) {
+		topics := make([]*KeywordTopic, maxComparisonItems+1)
+		for i := range topics {
+			topics[i] = &KeywordTopic{Mid: fmt.Sprintf("/m/%d", i)}
+		}
+
+		_, err := ExploreTopics(context.Background(), topics, locUS, "today 12-m", 0, langEN)
+		assert.Error(t, err)
+
+		_, err = ExploreTopics(context.Background(), nil, locUS, "today 12-m", 0, langEN)
+		assert.Error(t, err)
+
+		_, err = ExploreTopics(context.Background(), []*KeywordTopic{nil}, locUS, "today 12-m", 0, langEN)
+		assert.Error(t, err)
+	})
+}
+
+func TestWidgetDataTokenExpired(t *testing.T) {
+	const expiredBody = `)]}',{"status":"token expired"}`
+
+	newWidget := func(id string) *ExploreWidget {
+		return &ExploreWidget{
+			ID:    id,
+			Token: testToken,
+			Request: &WidgetResponse{
+				Restriction: WidgetComparisonItem{Geo: map[string]string{"country": locUS}},
+				CompItem:    []*WidgetComparisonItem{{Time: "today 12-m"}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name string
+		call func(ctx context.Context) error
+	}{
+		{
+			name: "interest over time",
+			call: func(ctx context.Context) error {
+				_, err := InterestOverTime(ctx, newWidget(string(IntOverTimeWidgetID)), langEN)
+				return err
+			},
+		},
+		{
+			name: "interest by location",
+			call: func(ctx context.Context) error {
+				_, err := InterestByLocation(ctx, newWidget(string(IntOverRegionID)), langEN)
+				return err
+			},
+		},
+		{
+			name: "related",
+			call: func(ctx context.Context) error {
+				_, err := Related(ctx, newWidget(string(RelatedQueriesID)), langEN)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useMockClient(t, func(req *http.Request) (*http.Response, error) {
+				return newMockResponse(http.StatusOK, expiredBody), nil
+			})
+
+			err := tt.call(context.Background())
+			assert.ErrorIs(t, err, ErrTokenExpired)
+		})
+	}
+
+	t.Run("empty data is not expired", func(t *testing.T) {
+		useMockClient(t, func(req *http.Request) (*http.Response, error) {
+			return newMockResponse(http.StatusOK, `)]}',{"default":{"timelineData":[]}}`), nil
+		})
+
+		timeline, err := InterestOverTime(context.Background(), newWidget(string(IntOverTimeWidgetID)), langEN)
+		assert.NoError(t, err)
+		assert.Empty(t, timeline)
+	})
+}
+
+func TestAllTimeRangeResolution(t *testing.T) {
+	t.Run("explore widget of all range requests monthly", func(t *testing.T) {
+		sent := new(WidgetResponse)
+		useMockClient(t, func(req *http.Request) (*http.Response, error) {
+			if strings.HasSuffix(req.URL.Path, gSExplore) {
+				return newMockResponse(http.StatusOK, `)]}'{"widgets":[`+
+					`{"id":"TIMESERIES","token":"`+testToken+`ts","request":{"resolution":"WEEK","comparisonItem":[`+
+					`{"time":"2004-01-01 2004-06-01","originalTimeRangeForExploreUrl":"all"}]}}]}`), nil
+			}
+			if err := json.Unmarshal([]byte(req.URL.Query().Get(paramReq)), sent); err != nil {
+				return nil, err
+			}
+			return newMockResponse(http.StatusOK, timelineBody), nil
+		})
+
+		widgets, err := Explore(context.Background(), &ExploreRequest{
+			ComparisonItems: []*ComparisonItem{{Keyword: "golang", Time: "all"}},
+		}, langEN)
+		require.NoError(t, err)
+		require.Len(t, widgets, 1)
+		// explore returns the widgets as google sent them
+		assert.Equal(t, resolutionWeek, widgets[0].Request.Resolution)
+
+		_, err = InterestOverTime(context.Background(), widgets[0], langEN)
+		require.NoError(t, err)
+		assert.Equal(t, resolutionMonth, sent.Resolution)
+	})
+
+	tests := []struct {
+		name               string
+		time               string
+		expectedResolution string
+	}{
+		{
+			name:               "all range requests monthly resolution",
+			time:               "all",
+			expectedResolution: resolutionMonth,
+		},
+		{
+			name:               "other ranges keep resolution",
+			time:               "today 12-m",
+			expectedResolution: resolutionWeek,
+		},
+		{
+			name:               "custom ranges get resolution from length",
+			time:               "2015-01-01 2023-12-31",
+			expectedResolution: resolutionMonth,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent := new(WidgetResponse)
+			useMockClient(t, func(req *http.Request) (*http.Response, error) {
+				if err := json.Unmarshal([]byte(req.URL.Query().Get(paramReq)), sent); err != nil {
+					return nil, err
+				}
+				return newMockResponse(http.StatusOK, timelineBody), nil
+			})
+
+			w := &ExploreWidget{
+				ID:    string(IntOverTimeWidgetID),
+				Token: testToken,
+				Request: &WidgetResponse{
+					Resolution: resolutionWeek,
+					CompItem:   []*WidgetComparisonItem{{Time: tt.time}},
+				},
+			}
+			_, err := InterestOverTime(context.Background(), w, langEN)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResolution, sent.Resolution)
+		})
+	}
+}
+
+func TestExploreNormalizesGeo(t *testing.T) {
+	sent := new(ExploreRequest)
+	useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		if err := json.Unmarshal([]byte(req.URL.Query().Get(paramReq)), sent); err != nil {
+			return nil, err
+		}
+		return newMockResponse(http.StatusOK, `)]}'{"widgets":[]}`), nil
+	})
+
+	_, err := Explore(context.Background(), &ExploreRequest{
+		ComparisonItems: []*ComparisonItem{
+			{Keyword: "golang", Geo: "usa", Time: "today 12-m"},
+			{Keyword: "python", Geo: "us-ca", Time: "today 12-m"},
+			{Keyword: "rust", Geo: "", Time: "today 12-m"},
+		},
+	}, langEN)
+
+	require.NoError(t, err)
+	require.Len(t, sent.ComparisonItems, 3)
+	assert.Equal(t, "US", sent.ComparisonItems[0].Geo)
+	assert.Equal(t, "US-CA", sent.ComparisonItems[1].Geo)
+	assert.Equal(t, "", sent.ComparisonItems[2].Geo)
+}
+
+func TestExploreDeduplicatesWidgets(t *testing.T) {
+	useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		return newMockResponse(http.StatusOK, `)]}'{"widgets":[{"id":"TIMESERIES","token":"a"},{"id":"GEO_MAP","token":"b"},{"id":"TIMESERIES","token":"c"}]}`), nil
+	})
+
+	widgets, err := Explore(context.Background(), &ExploreRequest{
+		ComparisonItems: []*ComparisonItem{{Keyword: "golang", Time: "today 12-m"}},
+	}, langEN)
+
+	require.NoError(t, err)
+	require.Len(t, widgets, 2)
+	assert.Equal(t, "a", widgets.GetWidgetsByType(IntOverTimeWidgetID)[0].Token)
+}
+
+func TestWithExploreCache(t *testing.T) {
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	c := useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		sent := new(ExploreRequest)
+		if err := json.Unmarshal([]byte(req.URL.Query().Get(paramReq)), sent); err != nil {
+			return nil, err
+		}
+
+		keyword := sent.ComparisonItems[0].Keyword
+		mu.Lock()
+		calls[keyword]++
+		mu.Unlock()
+		if keyword == "broken" {
+			return newMockResponse(http.StatusInternalServerError, ""), nil
+		}
+		return newMockResponse(http.StatusOK, `)]}'{"widgets":[{"id":"TIMESERIES","token":"ts"}]}`), nil
+	})
+	WithExploreCache(time.Minute)(c)
+
+	now := time.Date(2026, time.October, 14, 12, 0, 0, 0, time.UTC)
+	c.explores.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	explore := func(keyword, hl string) (ExploreResponse, error) {
+		return Explore(ctx, &ExploreRequest{
+			ComparisonItems: []*ComparisonItem{{Keyword: keyword, Geo: "US", Time: "today 12-m"}},
+		}, hl)
+	}
+
+	t.Run("hit", func(t *testing.T) {
+		widgets, err := explore("golang", langEN)
+		require.NoError(t, err)
+		widgets[0].Token = "modified"
+
+		widgets, err = explore("golang", langEN)
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls["golang"])
+		assert.Equal(t, "ts", widgets[0].Token)
+	})
+
+	t.Run("miss", func(t *testing.T) {
+		_, err := explore("golang", "DE")
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls["golang"])
+
+		_, err = explore("rust", langEN)
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls["rust"])
+	})
+
+	t.Run("keywords as sent", func(t *testing.T) {
+		_, err := explore(" go", langEN)
+		require.NoError(t, err)
+		_, err = explore("go", langEN)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, calls[" go"])
+		assert.Equal(t, 1, calls["go"])
+	})
+
+	t.Run("expiry", func(t *testing.T) {
+		now = now.Add(time.Minute)
+
+		_, err := explore("golang", langEN)
+		require.NoError(t, err)
+		assert.Equal(t, 3, calls["golang"])
+
+		_, err = explore("golang", langEN)
+		require.NoError(t, err)
+		assert.Equal(t, 3, calls["golang"])
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		_, err := explore("broken", langEN)
+		assert.ErrorIs(t, err, ErrRequestFailed)
+		_, err = explore("broken", langEN)
+		assert.ErrorIs(t, err, ErrRequestFailed)
+		assert.Equal(t, 2, calls["broken"])
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		wg := new(sync.WaitGroup)
+		for i := 0; i < concurrentGoroutinesNum; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				widgets, err := explore("golang", langEN)
+				assert.NoError(t, err)
+				assert.Len(t, widgets, 1)
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 3, calls["golang"])
+	})
+
+	t.Run("normalized keywords", func(t *testing.T) {
+		WithKeywordNormalization()(c)
+
+		// sent as "golang", which is cached since the expiry subtest
+		_, err := explore(" Golang ", langEN)
+		require.NoError(t, err)
+		assert.Equal(t, 3, calls["golang"])
+		assert.Zero(t, calls[" Golang "])
+	})
+}
+
+func TestExploreRequestGeo(t *testing.T) {
+	var raw string
+	useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		raw = req.URL.Query().Get(paramReq)
+		return newMockResponse(http.StatusOK, `)]}'{"widgets":[]}`), nil
+	})
+
+	_, err := Explore(context.Background(), &ExploreRequest{
+		ComparisonItems: []*ComparisonItem{
+			{Keyword: "golang", Time: "today 12-m"},
+			{Keyword: "python", Geo: "GB", Time: "today 12-m"},
+			{Keyword: "rust", Time: "today 12-m"},
+		},
+		Geo: "usa",
+	}, langEN)
+	require.NoError(t, err)
+
+	sent := new(ExploreRequest)
+	require.NoError(t, json.Unmarshal([]byte(raw), sent))
+	require.Len(t, sent.ComparisonItems, 3)
+	assert.Equal(t, "US", sent.ComparisonItems[0].Geo)
+	assert.Equal(t, "GB", sent.ComparisonItems[1].Geo)
+	assert.Equal(t, "US", sent.ComparisonItems[2].Geo)
+	assert.Empty(t, sent.Geo)
+	assert.NotContains(t, raw, `"geo":"usa"`)
+}
+
+func TestExploreKeywordType(t *testing.T) {
+	sent := new(ExploreRequest)
+	useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		if err := json.Unmarshal([]byte(req.URL.Query().Get(paramReq)), sent); err != nil {
+			return nil, err
+		}
+		return newMockResponse(http.StatusOK, `)]}'{"widgets":[]}`), nil
+	})
+
+	_, err := Explore(context.Background(), &ExploreRequest{
+		ComparisonItems: []*ComparisonItem{
+			{Keyword: "golang", Time: "today 12-m"},
+			{Keyword: "/m/09gbxjr", Time: "today 12-m"},
+			{Keyword: "/g/11bc6c1xld", Time: "today 12-m"},
+			{Keyword: "/m/forced", Time: "today 12-m", KeywordType: "QUERY"},
+		},
+	}, langEN)
+
+	require.NoError(t, err)
+	require.Len(t, sent.ComparisonItems, 4)
+	assert.Equal(t, "QUERY", sent.ComparisonItems[0].KeywordType)
+	assert.Equal(t, "ENTITY", sent.ComparisonItems[1].KeywordType)
+	assert.Equal(t, "ENTITY", sent.ComparisonItems[2].KeywordType)
+	assert.Equal(t, "QUERY", sent.ComparisonItems[3].KeywordType)
+}
+
+func TestConfigureAndReset(t *testing.T) {
+	orig := defaultClient()
+	t.Cleanup(func() { setDefaultClient(orig) })
+
+	var calls int
+	mock := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			calls++
+			return newMockResponse(http.StatusOK, `)]}'{"name":"All categories","id":0,"children":[{"name":"Programming","id":31}]}`), nil
+		},
+	}
+
+	Configure(WithHTTPClient(mock))
+	assert.Equal(t, mock, defaultClient().httpClient)
+
+	cats, err := ExploreCategories(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, catProgramming, cats.Children[0].ID)
+	assert.Equal(t, 1, calls)
+
+	// reconfiguring discards the cache of the previous client
+	Configure(WithHTTPClient(mock))
+	_, err = ExploreCategories(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	Reset()
+	assert.Equal(t, http.DefaultClient, defaultClient().httpClient)
+	assert.Nil(t, defaultClient().getCategories())
+}
+
+func TestNewClientIsolation(t *testing.T) {
+	t.Parallel()
+
+	// newTransport serves categories and autocomplete requests, rate limiting the
+	// first request with a cookie when limit is set; it records the sent cookies.
+	newTransport := func(limit bool) (*mockHTTPClient, *[]string, *int) {
+		var cookies []string
+		var categoryCalls int
+		mu := new(sync.Mutex)
+		return &mockHTTPClient{
+			doFunc: func(req *http.Request) (*http.Response, error) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				cookies = append(cookies, req.Header.Get(headerKeyCookie))
+				if limit {
+					limit = false
+					resp := newMockResponse(http.StatusTooManyRequests, "")
+					resp.Header.Set(headerKeySetCookie, "NID=us; Path=/")
+					return resp, nil
+				}
+
+				if strings.HasSuffix(req.URL.Path, gSCategories) {
+					categoryCalls++
+					return newMockResponse(http.StatusOK, `)]}'{"name":"All categories","id":0}`), nil
+				}
+				return newMockResponse(http.StatusOK, `)]}',{"default":{"topics":[]}}`), nil
+			},
+		}, &cookies, &categoryCalls
+	}
+
+	usTransport, usCookies, usCategoryCalls := newTransport(true)
+	euTransport, euCookies, euCategoryCalls := newTransport(false)
+	us := NewClient(WithHTTPClient(usTransport))
+	eu := NewClient(WithHTTPClient(euTransport))
+
+	ctx := context.Background()
+	for _, c := range []*Client{us, eu, us, eu} {
+		_, err := c.Search(ctx, "golang", langEN)
+		require.NoError(t, err)
+		_, err = c.ExploreCategories(ctx)
+		require.NoError(t, err)
+	}
+
+	// the rate limit cookie of one client is never sent by the other
+	assert.Equal(t, []string{"", "NID=us", "NID=us", "NID=us"}, *usCookies)
+	assert.Equal(t, []string{"", "", ""}, *euCookies)
+	assert.True(t, us.RateLimited())
+	assert.False(t, eu.RateLimited())
+
+	// each client caches categories on its own
+	assert.Equal(t, 1, *usCategoryCalls)
+	assert.Equal(t, 1, *euCategoryCalls)
+	assert.NotSame(t, us.gClient, defaultClient())
+	assert.NotSame(t, eu.gClient, defaultClient())
+
+	t.Run("prepared widget fetched with its client", func(t *testing.T) {
+		var calls int
+		c := NewClient(WithHTTPClient(&mockHTTPClient{
+			doFunc: func(req *http.Request) (*http.Response, error) {
+				calls++
+				return newMockResponse(http.StatusOK, timelineBody), nil
+			},
+		}))
+
+		prepared, err := c.Prepare(newTimelineWidget(1))
+		require.NoError(t, err)
+		_, err = prepared.FetchTimeline(ctx, langEN)
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestNewClientLinkBase(t *testing.T) {
+	t.Parallel()
+
+	keyword := &RankedKeyword{Query: "golang", Link: "/trends/explore?q=golang"}
+	trend := &TrendingSearch{Title: &SearchTitle{Query: "golang"}}
+
+	mirror := NewClient(WithLinkBase("https://mirror.example.com"))
+	proxy := NewClient(WithLinkBase("https://proxy.example.com/"))
+
+	assert.Equal(t, "https://mirror.example.com/trends/explore?q=golang", mirror.FullLink(keyword))
+	assert.Equal(t, "https://proxy.example.com/trends/explore?q=golang", proxy.FullLink(keyword))
+	assert.Equal(t, "https://mirror.example.com/trends/explore?q=golang", mirror.TrendURL(trend))
+	assert.Equal(t, "https://proxy.example.com/trends/explore?q=golang", proxy.TrendURL(trend))
+
+	var buf bytes.Buffer
+	require.NoError(t, mirror.RelatedToCSV(&buf, []*RankedKeyword{keyword}))
+	assert.Contains(t, buf.String(), "https://mirror.example.com/trends/explore?q=golang")
+	buf.Reset()
+	require.NoError(t, proxy.RelatedToCSV(&buf, []*RankedKeyword{keyword}))
+	assert.Contains(t, buf.String(), "https://proxy.example.com/trends/explore?q=golang")
+
+	// the package-level client is left untouched
+	assert.Equal(t, gHost+"/trends/explore?q=golang", keyword.FullLink())
+	assert.Equal(t, gHost+"/trends/explore?q=golang", trend.URL())
+}
+
+func TestConfigureConcurrent(t *testing.T) {
+	orig := defaultClient()
+	t.Cleanup(func() { setDefaultClient(orig) })
+
+	mock := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			return newMockResponse(http.StatusOK, `)]}'{"widgets":[]}`), nil
+		},
+	}
+	Configure(WithHTTPClient(mock))
+
+	wg := new(sync.WaitGroup)
+	for i := 0; i < concurrentGoroutinesNum; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			Configure(WithHTTPClient(mock))
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := Explore(context.Background(), &ExploreRequest{
+				ComparisonItems: []*ComparisonItem{{Keyword: "golang", Time: "today 12-m"}},
+			}, langEN)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestInterestOverTimeNormalized(t *testing.T) {
+	useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		return newMockResponse(http.StatusOK, `)]}',{"default":{"timelineData":[`+
+			`{"time":"1609459200","value":[10,0],"hasData":[true,false]},`+
+			`{"time":"1609545600","value":[40,20],"hasData":[true,true]}]}}`), nil
+	})
+
+	timeline, scaled, err := InterestOverTimeNormalized(context.Background(), newTimelineWidget(2), langEN)
+
+	require.NoError(t, err)
+	require.Len(t, timeline, 2)
+	require.Len(t, scaled, 2)
+	assert.Equal(t, []int{40, 20}, timeline[1].Value)
+	assert.Equal(t, 0.0, scaled[0][0])
+	assert.True(t, math.IsNaN(scaled[0][1]))
+	assert.Equal(t, []float64{1, 0}, scaled[1])
+
+	_, _, err = InterestOverTimeNormalized(context.Background(), &ExploreWidget{ID: string(IntOverRegionID)}, langEN)
+	assert.ErrorIs(t, err, ErrInvalidWidgetType)
+}
+
+func TestInterestOverTimeFilled(t *testing.T) {
+	useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		return newMockResponse(http.StatusOK, `)]}',{"default":{"timelineData":[`+
+			`{"time":"1609459200","value":[10],"hasData":[true],"formattedValue":["10"]},`+
+			`{"time":"1609545600","value":[0],"hasData":[false],"formattedValue":["0"]}]}}`), nil
+	})
+
+	timeline, err := InterestOverTimeFilled(context.Background(), newTimelineWidget(1), langEN)
+
+	require.NoError(t, err)
+	require.Len(t, timeline, 2)
+	assert.Equal(t, []int{10}, timeline[1].Value)
+	assert.Equal(t, []bool{true}, timeline[1].Filled)
+	assert.Equal(t, []bool{false}, timeline[0].Filled)
+}
+
+func TestInterestOverTimeWithAverages(t *testing.T) {
+	var body string
+	useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		return newMockResponse(http.StatusOK, body), nil
+	})
+
+	t.Run("extracts averages", func(t *testing.T) {
+		body = `)]}',{"default":{"timelineData":[` +
+			`{"time":"1609459200","value":[80,20],"hasData":[true,true]},` +
+			`{"time":"1609545600","value":[60,30],"hasData":[true,true]}],"averages":[70,25]}}`
+
+		timeline, averages, err := InterestOverTimeWithAverages(context.Background(), newTimelineWidget(2), langEN)
+
+		require.NoError(t, err)
+		require.Len(t, timeline, 2)
+		assert.Equal(t, []int{60, 30}, timeline[1].Value)
+		assert.Equal(t, []int{70, 25}, averages)
+	})
+
+	t.Run("missing averages", func(t *testing.T) {
+		body = `)]}',{"default":{"timelineData":[{"time":"1609459200","value":[80],"hasData":[true]}]}}`
+
+		timeline, averages, err := InterestOverTimeWithAverages(context.Background(), newTimelineWidget(1), langEN)
+
+		require.NoError(t, err)
+		assert.Len(t, timeline, 1)
+		assert.NotNil(t, averages)
+		assert.Empty(t, averages)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		body = `)]}',{}`
+
+		_, _, err := InterestOverTimeWithAverages(context.Background(), newTimelineWidget(1), langEN)
+
+		assert.ErrorIs(t, err, ErrTokenExpired)
+	})
+}
+
+func TestSearchGeo(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []SearchOption
+		expectedGeo string
+		hasGeo      bool
+	}{
+		{
+			name: "default omits geo",
+		},
+		{
+			name:        "geo is sent",
+			opts:        []SearchOption{WithSearchGeo("DE")},
+			expectedGeo: "DE",
+			hasGeo:      true,
+		},
+		{
+			name:        "geo is normalized",
+			opts:        []SearchOption{WithSearchGeo("uk")},
+			expectedGeo: "GB",
+			hasGeo:      true,
+		},
+		{
+			name: "empty geo omits param",
+			opts: []SearchOption{WithSearchGeo("")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query url.Values
+			useMockClient(t, func(req *http.Request) (*http.Response, error) {
+				query = req.URL.Query()
+				return newMockResponse(http.StatusOK, `)]}',{"default":{"topics":[{"mid":"/m/09gbxjr","title":"Go","type":"Programming language"}]}}`), nil
+			})
+
+			topics, err := Search(context.Background(), "golang", langEN, tt.opts...)
+			require.NoError(t, err)
+			require.Len(t, topics, 1)
+
+			_, ok := query[paramGeo]
+			assert.Equal(t, tt.hasGeo, ok)
+			assert.Equal(t, tt.expectedGeo, query.Get(paramGeo))
+			assert.Equal(t, langEN, query.Get(paramHl))
+		})
+	}
+}
+
+func TestSearchCategory(t *testing.T) {
+	var query url.Values
+	useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		query = req.URL.Query()
+		return newMockResponse(http.StatusOK, `)]}',{"default":{"topics":[{"mid":"/m/0k8z","title":"Apple","type":"Technology company"}]}}`), nil
+	})
+
+	ctx := context.Background()
+
+	_, err := Search(ctx, "apple", langEN, WithSearchCategory(5))
+	require.NoError(t, err)
+	assert.Equal(t, "5", query.Get(paramCat))
+
+	_, err = Search(ctx, "apple", langEN, WithSearchCategory(0))
+	require.NoError(t, err)
+	_, ok := query[paramCat]
+	assert.False(t, ok)
+}
+
+func TestResolveEntities(t *testing.T) {
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		word := strings.TrimPrefix(req.URL.Path, "/trends/api"+gSAutocomplete+"/")
+
+		mu.Lock()
+		calls[word]++
+		mu.Unlock()
+
+		switch word {
+		case "golang":
+			return newMockResponse(http.StatusOK, `)]}',{"default":{"topics":[`+
+				`{"mid":"/m/01","title":"Golang tutorial","type":"Topic"},`+
+				`{"mid":"/m/09gbxjr","title":"Golang","type":"Programming language"}]}}`), nil
+		case "rust":
+			return newMockResponse(http.StatusOK, `)]}',{"default":{"topics":[`+
+				`{"title":"rust"},{"mid":"/m/0dsbpg6","title":"Rust","type":"Programming language"}]}}`), nil
+		case "zig":
+			return newMockResponse(http.StatusOK, `)]}',{"default":{"topics":[{"title":"zig"}]}}`), nil
+		}
+
+		return newMockResponse(http.StatusInternalServerError, ""), nil
+	})
+
+	out, err := ResolveEntities(context.Background(), []string{"golang", "rust", "zig", "broken", "golang"}, langEN)
+
+	assert.ErrorIs(t, err, ErrRequestFailed)
+	assert.Contains(t, err.Error(), `"broken"`)
+
+	require.Len(t, out, 3)
+	assert.Equal(t, "/m/09gbxjr", out["golang"].Mid)
+	assert.Equal(t, "/m/0dsbpg6", out["rust"].Mid)
+	v, ok := out["zig"]
+	assert.True(t, ok)
+	assert.Nil(t, v)
+	assert.NotContains(t, out, "broken")
+	assert.Equal(t, 1, calls["golang"])
+}
+
+func TestDailyMultiLang(t *testing.T) {
+	useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		hl := req.URL.Query().Get(paramHl)
+		if hl == "IT" {
+			return newMockResponse(http.StatusInternalServerError, ""), nil
+		}
+		return newMockResponse(http.StatusOK, newBatchResponse(t, []interface{}{"trend " + hl})), nil
+	})
+
+	byLang, err := DailyMultiLang(context.Background(), []string{"DE", "FR", "IT"}, "CH")
+
+	assert.ErrorIs(t, err, ErrRequestFailed)
+	assert.Contains(t, err.Error(), "IT: ")
+	require.Len(t, byLang, 2)
+	for _, hl := range []string{"DE", "FR"} {
+		require.Len(t, byLang[hl], 1)
+		assert.Equal(t, "trend "+hl, byLang[hl][0].Title.Query)
+	}
+}
+
+func TestDailyByCategories(t *testing.T) {
+	useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		_, args := decodeTrendsPayload(t, string(body))
+		cat := int(args[3].(float64))
+		if cat == 20 {
+			return newMockResponse(http.StatusInternalServerError, ""), nil
+		}
+		return newMockResponse(http.StatusOK, newBatchResponse(t, []interface{}{fmt.Sprintf("trend %d", cat)})), nil
+	})
+
+	byCat, err := DailyByCategories(context.Background(), "EN", "US", []int{0, 17, 18, 20})
+
+	assert.ErrorIs(t, err, ErrRequestFailed)
+	assert.Contains(t, err.Error(), "category 20: ")
+	require.Len(t, byCat, 3)
+	for _, cat := range []int{0, 17, 18} {
+		require.Len(t, byCat[cat], 1)
+		assert.Equal(t, fmt.Sprintf("trend %d", cat), byCat[cat][0].Title.Query)
+	}
+}
+
+func TestDailyMultiStream(t *testing.T) {
+	useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		_, args := decodeTrendsPayload(t, string(body))
+		loc := args[2].(string)
+		if loc == "FR" {
+			return newMockResponse(http.StatusInternalServerError, ""), nil
+		}
+		return newMockResponse(http.StatusOK, newBatchResponse(t, []interface{}{loc + " 1"}, []interface{}{loc + " 2"})), nil
+	})
+
+	t.Run("streams tagged trends and closes", func(t *testing.T) {
+		out := make(chan struct {
+			Loc    string
+			Search *TrendingSearch
+		})
+		errc := make(chan error, 1)
+		go func() {
+			errc <- DailyMultiStream(context.Background(), langEN, []string{"US", "GB", "FR", "DE"}, out)
+		}()
+
+		byLoc := make(map[string][]string)
+		for s := range out {
+			byLoc[s.Loc] = append(byLoc[s.Loc], s.Search.Title.Query)
+		}
+
+		err := <-errc
+		assert.ErrorIs(t, err, ErrRequestFailed)
+		assert.Contains(t, err.Error(), "FR: ")
+		assert.Equal(t, map[string][]string{
+			"US": {"US 1", "US 2"},
+			"GB": {"GB 1", "GB 2"},
+			"DE": {"DE 1", "DE 2"},
+		}, byLoc)
+	})
+
+	t.Run("cancellation abandons sends", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		out := make(chan struct {
+			Loc    string
+			Search *TrendingSearch
+		})
+		errc := make(chan error, 1)
+		go func() {
+			errc <- DailyMultiStream(ctx, langEN, []string{"US", "GB"}, out)
+		}()
+
+		<-out
+		cancel()
+
+		err := <-errc
+		assert.ErrorIs(t, err, context.Canceled)
+		_, open := <-out
+		assert.False(t, open)
+	})
+}
+
+func TestDailyMerged(t *testing.T) {
+	const legacyBody = `)]}',{"default":{"trendingSearchesDays":[` +
+		`{"formattedDate":"Wednesday, October 14, 2026","trendingSearches":[` +
+		`{"title":{"query":"Golang"},"formattedTraffic":"100K+","image":{"imageUrl":"https://img.example/go.jpg"},` +
+		`"shareUrl":"https://trends.google.com/trends/trendingsearches/daily?geo=US#Golang",` +
+		`"articles":[{"title":"Go 1.23 released","source":"Go Blog","url":"https://go.dev/blog","snippet":"Iterators"}]}]},` +
+		`{"formattedDate":"Tuesday, October 13, 2026","trendingSearches":[` +
+		`{"title":{"query":"golang"},"formattedTraffic":"20K+"},` +
+		`{"title":{"query":"zig"},"formattedTraffic":"5K+"}]}]}}`
+
+	newBody := newBatchResponse(t,
+		[]interface{}{"golang"},
+		[]interface{}{"rust", []interface{}{[]interface{}{"Rust 2024", "https://blog.rust-lang.org", "Rust Blog"}}},
+	)
+
+	tests := []struct {
+		name          string
+		newStatus     int
+		legacyStatus  int
+		expectedErr   bool
+		expectedQuery []string
+		expectedTraff []string
+		verify        func(t *testing.T, trends []*TrendingSearch)
+	}{
+		{
+			name:          "enriches new results",
+			newStatus:     http.StatusOK,
+			legacyStatus:  http.StatusOK,
+			expectedQuery: []string{"golang", "rust"},
+			expectedTraff: []string{"100K+", ""},
+			verify: func(t *testing.T, trends []*TrendingSearch) {
+				assert.True(t, trends[0].HasImage())
+				assert.Equal(t, "https://trends.google.com/trends/trendingsearches/daily?geo=US#Golang", trends[0].ShareURL)
+				assert.Empty(t, trends[1].ShareURL)
+				require.Len(t, trends[0].Articles, 1)
+				assert.Equal(t, "Iterators", trends[0].Articles[0].Snippet)
+				require.Len(t, trends[1].Articles, 1)
+				assert.Equal(t, "Rust Blog", trends[1].Articles[0].Source)
+			},
+		},
+		{
+			name:          "legacy failure keeps new results",
+			newStatus:     http.StatusOK,
+			legacyStatus:  http.StatusInternalServerError,
+			expectedQuery: []string{"golang", "rust"},
+			expectedTraff: []string{"", ""},
+		},
+		{
+			name:          "new failure falls back to latest legacy day",
+			newStatus:     http.StatusInternalServerError,
+			legacyStatus:  http.StatusOK,
+			expectedQuery: []string{"Golang"},
+			expectedTraff: []string{"100K+"},
+		},
+		{
+			name:         "both failures",
+			newStatus:    http.StatusInternalServerError,
+			legacyStatus: http.StatusInternalServerError,
+			expectedErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useMockClient(t, func(req *http.Request) (*http.Response, error) {
+				if strings.HasSuffix(req.URL.Path, gSDaily) {
+					assert.Equal(t, locUS, req.URL.Query().Get(paramGeo))
+					return newMockResponse(tt.legacyStatus, legacyBody), nil
+				}
+				return newMockResponse(tt.newStatus, newBody), nil
+			})
+
+			trends, err := DailyMerged(context.Background(), langEN, "usa")
+			if tt.expectedErr {
+				assert.ErrorIs(t, err, ErrRequestFailed)
+				return
+			}
+			require.NoError(t, err)
+
+			queries := make([]string, 0, len(trends))
+			traffic := make([]string, 0, len(trends))
+			for _, v := range trends {
+				queries = append(queries, v.Title.Query)
+				traffic = append(traffic, v.FormattedTraffic)
+			}
+			assert.Equal(t, tt.expectedQuery, queries)
+			assert.Equal(t, tt.expectedTraff, traffic)
+
+			if tt.verify != nil {
+				tt.verify(t, trends)
+			}
+		})
+	}
+}
+
+func TestDailyTop(t *testing.T) {
+	useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		return newMockResponse(http.StatusOK, newBatchResponse(t,
+			[]interface{}{"golang"}, []interface{}{"rust"}, []interface{}{"zig"})), nil
+	})
+
+	tests := []struct {
+		name        string
+		n           int
+		expected    []string
+		expectedErr bool
+	}{
+		{name: "first n", n: 2, expected: []string{"golang", "rust"}},
+		{name: "n larger than result count", n: 10, expected: []string{"golang", "rust", "zig"}},
+		{name: "zero n", n: 0, expectedErr: true},
+		{name: "negative n", n: -1, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trends, err := DailyTop(context.Background(), langEN, locUS, tt.n)
+			if tt.expectedErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), errInvalidRequest)
+				return
+			}
+			require.NoError(t, err)
+
+			queries := make([]string, 0, len(trends))
+			for _, v := range trends {
+				queries = append(queries, v.Title.Query)
+			}
+			assert.Equal(t, tt.expected, queries)
+		})
+	}
+}
+
+func TestMissingToken(t *testing.T) {
+	var requests int
+	useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		requests++
+		return newMockResponse(http.StatusOK, timelineBody), nil
+	})
+
+	assert.True(t, (&ExploreWidget{Token: testToken}).HasValidToken())
+
+	for _, token := range []string{"", "APP6_UEAA"} {
+		newWidget := func(id string) *ExploreWidget {
+			return &ExploreWidget{
+				ID:    id,
+				Token: token,
+				Request: &WidgetResponse{
+					Restriction: WidgetComparisonItem{Geo: map[string]string{"country": locUS}},
+					CompItem:    []*WidgetComparisonItem{{Time: "today 12-m"}},
+				},
+			}
+		}
+
+		assert.False(t, newWidget(string(IntOverTimeWidgetID)).HasValidToken())
+
+		_, err := InterestOverTime(context.Background(), newWidget(string(IntOverTimeWidgetID)), langEN)
+		assert.ErrorIs(t, err, ErrMissingToken)
+
+		_, err = InterestByLocation(context.Background(), newWidget(string(IntOverRegionID)), langEN)
+		assert.ErrorIs(t, err, ErrMissingToken)
+
+		_, err = Related(context.Background(), newWidget(string(RelatedQueriesID)), langEN)
+		assert.ErrorIs(t, err, ErrMissingToken)
+
+		_, err = Prepare(newWidget(string(RelatedTopicsID)))
+		assert.ErrorIs(t, err, ErrMissingToken)
+	}
+
+	assert.Zero(t, requests)
+}
+
+func TestDailyDays(t *testing.T) {
+	useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		assert.True(t, strings.HasSuffix(req.URL.Path, gSDaily))
+		return newMockResponse(http.StatusOK, `)]}',{"default":{"trendingSearchesDays":[`+
+			`{"date":"20261014","formattedDate":"Wednesday, October 14, 2026","trendingSearches":[`+
+			`{"title":{"query":"golang"},"formattedTraffic":"100K+",`+
+			`"image":{"newsUrl":"https://go.dev/blog","source":"Go Blog","imageUrl":"https://img.example/go.jpg"},`+
+			`"articles":[{"title":"Go 1.23 released","timeAgo":"2h ago","source":"Go Blog","url":"https://go.dev/blog","snippet":"Iterators"}]}]},`+
+			`{"date":"20261013","formattedDate":"Tuesday, October 13, 2026","trendingSearches":[`+
+			`{"title":{"query":"rust"},"formattedTraffic":"20K+","articles":[]}]}]}}`), nil
+	})
+
+	days, err := DailyDays(context.Background(), langEN, locUS)
+
+	require.NoError(t, err)
+	require.Len(t, days, 2)
+	assert.Equal(t, "20261014", days[0].Date)
+	assert.Equal(t, "Tuesday, October 13, 2026", days[1].FormattedDate)
+
+	require.Len(t, days[0].Searches, 1)
+	golang := days[0].Searches[0]
+	assert.Equal(t, "100K+", golang.FormattedTraffic)
+	assert.True(t, golang.HasImage())
+	require.Len(t, golang.Articles, 1)
+	assert.Equal(t, "Iterators", golang.Articles[0].Snippet)
+
+	require.Len(t, days[1].Searches, 1)
+	assert.Equal(t, "rust", days[1].Searches[0].Title.Query)
+	assert.False(t, days[1].Searches[0].HasImage())
+}
+
+func TestDailyOnDate(t *testing.T) {
+	yesterday := time.Now().AddDate(0, 0, -1)
+	ed := yesterday.Format(dailyDateLayout)
+
+	var query url.Values
+	useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		query = req.URL.Query()
+		return newMockResponse(http.StatusOK, `)]}',{"default":{"trendingSearchesDays":[`+
+			`{"date":"`+ed+`","trendingSearches":[{"title":{"query":"golang"},"formattedTraffic":"100K+"}]},`+
+			`{"date":"19990101","trendingSearches":[{"title":{"query":"rust"}}]}]}}`), nil
+	})
+
+	t.Run("past day", func(t *testing.T) {
+		trends, err := DailyOnDate(context.Background(), langEN, locUS, yesterday)
+
+		require.NoError(t, err)
+		assert.Equal(t, ed, query.Get(paramEndDate))
+		require.Len(t, trends, 1)
+		assert.Equal(t, "golang", trends[0].Title.Query)
+		assert.Equal(t, "100K+", trends[0].FormattedTraffic)
+	})
+
+	t.Run("day missing from response", func(t *testing.T) {
+		trends, err := DailyOnDate(context.Background(), langEN, locUS, time.Now().AddDate(0, 0, -3))
+
+		require.NoError(t, err)
+		assert.Empty(t, trends)
+	})
+
+	for name, date := range map[string]time.Time{
+		"future day":       time.Now().AddDate(0, 0, 1),
+		"beyond retention": time.Now().AddDate(0, 0, -dailyRetentionDays-1),
+	} {
+		t.Run(name, func(t *testing.T) {
+			query = nil
+			_, err := DailyOnDate(context.Background(), langEN, locUS, date)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), errInvalidRequest)
+			assert.Nil(t, query)
+		})
+	}
+}
+
+func TestWithTimezone(t *testing.T) {
+	t.Parallel()
+
+	const exploreBody = `)]}'{"widgets":[{"id":"TIMESERIES","token":"` + testToken + `","request":{"time":"today 12-m"}}]}`
+
+	var tz []string
+	mu := new(sync.Mutex)
+	transport := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			tz = append(tz, req.URL.Query().Get(paramTZ))
+			mu.Unlock()
+
+			if strings.HasSuffix(req.URL.Path, gSExplore) {
+				return newMockResponse(http.StatusOK, exploreBody), nil
+			}
+			return newMockResponse(http.StatusOK, timelineBody), nil
+		},
+	}
+	c := NewClient(WithHTTPClient(transport), WithTimezone(300))
+
+	ctx := context.Background()
+	widgets, err := c.Explore(ctx, &ExploreRequest{
+		ComparisonItems: []*ComparisonItem{{Keyword: "golang", Geo: locUS, Time: "today 12-m"}},
+	}, langEN)
+	require.NoError(t, err)
+	_, err = c.InterestOverTime(ctx, widgets[0], langEN)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"300", "300"}, tz)
+	assert.Equal(t, "300", c.defaultParams().Get(paramTZ))
+	assert.Equal(t, "0", newGClient().defaultParams().Get(paramTZ))
+}
+
+func TestWithKeywordNormalization(t *testing.T) {
+	t.Parallel()
+
+	send := func(t *testing.T, opts ...Option) []string {
+		var sent *ExploreRequest
+		transport := &mockHTTPClient{
+			doFunc: func(req *http.Request) (*http.Response, error) {
+				sent = new(ExploreRequest)
+				if err := json.Unmarshal([]byte(req.URL.Query().Get(paramReq)), sent); err != nil {
+					return nil, err
+				}
+				return newMockResponse(http.StatusOK, `)]}'{"widgets":[]}`), nil
+			},
+		}
+		c := NewClient(append([]Option{WithHTTPClient(transport)}, opts...)...)
+
+		newRequest := func() *ExploreRequest {
+			return &ExploreRequest{
+				ComparisonItems: []*ComparisonItem{
+					{Keyword: "  GoLang ", Time: "today+12-m"},
+					{Keyword: "/g/11bWxyZ", Geo: "us", Time: "today 12-m"},
+					{Keyword: "/m/09gbxjr", Geo: locUS, Time: "today 12-m"},
+				},
+				Geo: locUS,
+			}
+		}
+		r := newRequest()
+
+		_, err := c.Explore(context.Background(), r, langEN)
+		require.NoError(t, err)
+		// the caller's request is left as it was
+		assert.Equal(t, newRequest(), r)
+
+		keywords := make([]string, 0, len(sent.ComparisonItems))
+		for _, item := range sent.ComparisonItems {
+			keywords = append(keywords, item.Keyword)
+		}
+		return keywords
+	}
+
+	assert.Equal(t, []string{"  GoLang ", "/g/11bWxyZ", "/m/09gbxjr"}, send(t))
+	assert.Equal(t, []string{"golang", "/g/11bWxyZ", "/m/09gbxjr"}, send(t, WithKeywordNormalization()))
+}
+
+func TestSelfTest(t *testing.T) {
+	const exploreBody = `)]}'{"widgets":[{"id":"TIMESERIES","token":"` + testToken + `","request":{"time":"today 12-m"}}]}`
+	const timelineBody = `)]}',{"default":{"timelineData":[{"time":"1609459200","value":[80],"hasData":[true]}]}}`
+
+	tests := []struct {
+		name          string
+		explore       *http.Response
+		timeline      *http.Response
+		expectedStage string
+		expectedErr   error
+		expectedParse bool
+	}{
+		{
+			name:     "healthy",
+			explore:  newMockResponse(http.StatusOK, exploreBody),
+			timeline: newMockResponse(http.StatusOK, timelineBody),
+		},
+		{
+			name:          "explore request fails",
+			explore:       newMockResponse(http.StatusInternalServerError, ""),
+			expectedStage: "self test explore",
+			expectedErr:   ErrRequestFailed,
+		},
+		{
+			name:          "no timeline widget",
+			explore:       newMockResponse(http.StatusOK, `)]}'{"widgets":[]}`),
+			expectedStage: "self test token",
+			expectedErr:   ErrMissingToken,
+		},
+		{
+			name:          "timeline request fails",
+			explore:       newMockResponse(http.StatusOK, exploreBody),
+			timeline:      newMockResponse(http.StatusTooManyRequests, ""),
+			expectedStage: "self test timeline",
+			expectedErr:   ErrRequestFailed,
+		},
+		{
+			name:          "timeline format changed",
+			explore:       newMockResponse(http.StatusOK, exploreBody),
+			timeline:      newMockResponse(http.StatusOK, `)]}',{"default":`),
+			expectedStage: "self test parse",
+			expectedParse: true,
+		},
+		{
+			name:          "empty timeline",
+			explore:       newMockResponse(http.StatusOK, exploreBody),
+			timeline:      newMockResponse(http.StatusOK, `)]}',{"default":{"timelineData":[]}}`),
+			expectedStage: "self test parse",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useMockClient(t, func(req *http.Request) (*http.Response, error) {
+				if strings.HasSuffix(req.URL.Path, gSExplore) {
+					return tt.explore, nil
+				}
+				return tt.timeline, nil
+			})
+
+			err := SelfTest(context.Background(), langEN)
+
+			if len(tt.expectedStage) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedStage)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			}
+			var parseErr *ParseError
+			assert.Equal(t, tt.expectedParse, errors.As(err, &parseErr))
+		})
+	}
+}
+
+func TestNullRequestWidget(t *testing.T) {
+	var calls int
+	c := useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		calls++
+		return newMockResponse(http.StatusOK, `)]}'{"widgets":[`+
+			`{"id":"TIMESERIES","token":"`+testToken+`","request":null},`+
+			`{"id":"RELATED_QUERIES","token":"`+testToken+`","request":{"restriction":{"geo":{"country":"US"}}}}]}`), nil
+	})
+
+	ctx := context.Background()
+	request := &ExploreRequest{ComparisonItems: []*ComparisonItem{{Keyword: "golang", Time: "today 12-m"}}}
+
+	widgets, err := Explore(ctx, request, langEN)
+	require.NoError(t, err)
+	require.Len(t, widgets, 2)
+
+	widget := widgets.GetWidgetsByType(IntOverTimeWidgetID)[0]
+	require.Nil(t, widget.Request)
+
+	_, err = InterestOverTime(ctx, widget, langEN)
+	assert.ErrorIs(t, err, ErrMissingRequest)
+
+	_, err = InterestByLocation(ctx, &ExploreWidget{ID: string(IntOverRegionID), Token: testToken}, langEN)
+	assert.ErrorIs(t, err, ErrMissingRequest)
+
+	_, err = Related(ctx, &ExploreWidget{ID: string(RelatedTopicsID), Token: testToken}, langEN)
+	assert.ErrorIs(t, err, ErrMissingRequest)
+
+	_, err = Prepare(widget)
+	assert.ErrorIs(t, err, ErrMissingRequest)
+	assert.Equal(t, 1, calls)
+
+	WithSkipEmptyWidgets()(c)
+	widgets, err = Explore(ctx, request, langEN)
+	require.NoError(t, err)
+	require.Len(t, widgets, 1)
+	assert.Equal(t, string(RelatedQueriesID), widgets[0].ID)
+}
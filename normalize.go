@@ -0,0 +1,107 @@
+package googletrends
+
+import (
+	"fmt"
+	"strings"
+)
+
+// geoAliases maps common non-ISO country names to the ISO 3166-1 alpha-2 codes Google expects.
+var geoAliases = map[string]string{
+	"USA": "US",
+	"UK":  "GB",
+	"UAE": "AE",
+}
+
+// NormalizeGeo converts a geographic code to the form Google Trends expects.
+// It trims whitespace, uppercases the code and maps common aliases such as
+// "USA" to "US" and "UK" to "GB". Sub-region codes are kept intact apart from
+// casing ("us-ca" becomes "US-CA"), and an empty code stays empty (worldwide).
+//
+// Explore and DailyNew normalize their geo codes automatically, since Google
+// silently returns empty results for codes like "us" or "USA".
+//
+// Example:
+//
+//	googletrends.NormalizeGeo("usa")   // "US"
+//	googletrends.NormalizeGeo(" Uk ")  // "GB"
+//	googletrends.NormalizeGeo("us-ca") // "US-CA"
+func NormalizeGeo(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if alias, ok := geoAliases[code]; ok {
+		return alias
+	}
+
+	return code
+}
+
+// exploreProperties are the Google properties Explore accepts, keyed by their request value.
+var exploreProperties = map[string]string{
+	"":        "web search",
+	"images":  "image search",
+	"news":    "news search",
+	"froogle": "Google Shopping",
+	"youtube": "YouTube search",
+}
+
+// unsupportedCategories are the categories known to return empty data for a property,
+// keyed by the property request value.
+var unsupportedCategories = map[string]map[int]struct{}{
+	// Shopping
+	"news": {18: {}},
+	// News, Online Communities
+	"froogle": {16: {}, 299: {}},
+}
+
+// ValidateExploreCombo checks a category and property combination before it is sent
+// to Explore, since Google silently returns empty data for invalid ones instead of
+// an error. The property must be one of "", "images", "news", "froogle" or "youtube"
+// (lowercase), the category must not be negative and the pair must not be one known
+// to return no data, such as the Shopping category (18) on "news". When the category
+// tree has been fetched with ExploreCategories, the category must also exist in it.
+//
+// Explore runs this check itself when the client is configured with WithStrictValidation.
+//
+// Example:
+//
+//	if err := googletrends.ValidateExploreCombo(31, "youtube"); err != nil {
+//	    log.Fatal(err)
+//	}
+func ValidateExploreCombo(category int, property string) error {
+	return std().ValidateExploreCombo(category, property)
+}
+
+// ValidateExploreCombo is like the package-level ValidateExploreCombo, using the client c.
+func (c *Client) ValidateExploreCombo(category int, property string) error {
+	if _, ok := exploreProperties[property]; !ok {
+		return fmt.Errorf("%s: unknown property %q for category %d", errInvalidRequest, property, category)
+	}
+
+	if category < 0 {
+		return fmt.Errorf("%s: negative category %d for %s", errInvalidRequest, category, exploreProperties[property])
+	}
+
+	if _, ok := unsupportedCategories[property][category]; ok {
+		return fmt.Errorf("%s: category %d is not supported for property %q", errInvalidRequest, category, property)
+	}
+
+	if cats := c.getCategories(); cats != nil && !cats.contains(category) {
+		return fmt.Errorf("%s: unknown category %d for %s", errInvalidRequest, category, exploreProperties[property])
+	}
+
+	return nil
+}
+
+// contains reports whether the tree holds the category with the given ID.
+func (t *ExploreCatTree) contains(id int) bool {
+	if t.ID == id {
+		return true
+	}
+
+	for _, child := range t.Children {
+		if child != nil && child.contains(id) {
+			return true
+		}
+	}
+
+	return false
+}
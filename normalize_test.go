@@ -0,0 +1,116 @@
+package googletrends
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeGeo(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		code     string
+		expected string
+	}{
+		{code: "US", expected: "US"},
+		{code: "us", expected: "US"},
+		{code: "Us", expected: "US"},
+		{code: "USA", expected: "US"},
+		{code: "usa", expected: "US"},
+		{code: "UK", expected: "GB"},
+		{code: " gb ", expected: "GB"},
+		{code: "US-CA", expected: "US-CA"},
+		{code: "us-ca", expected: "US-CA"},
+		{code: "US-CA-803", expected: "US-CA-803"},
+		{code: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeGeo(tt.code))
+		})
+	}
+}
+
+func TestValidateExploreCombo(t *testing.T) {
+	calls := 0
+	c := useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		calls++
+		return newMockResponse(http.StatusOK, `)]}'{"widgets":[]}`), nil
+	})
+
+	tests := []struct {
+		name        string
+		category    int
+		property    string
+		expectedErr string
+	}{
+		{name: "web search", category: 0, property: ""},
+		{name: "youtube category", category: catProgramming, property: "youtube"},
+		{name: "unknown property", category: 0, property: "maps", expectedErr: `unknown property "maps"`},
+		{name: "property is case sensitive", category: 0, property: "YouTube", expectedErr: `unknown property "YouTube"`},
+		{name: "negative category", category: -1, property: "news", expectedErr: "negative category -1 for news search"},
+		{name: "unsupported pair", category: 18, property: "news", expectedErr: `category 18 is not supported for property "news"`},
+		{name: "category supported elsewhere", category: 18, property: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateExploreCombo(tt.category, tt.property)
+			if len(tt.expectedErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+
+	t.Run("cached category tree", func(t *testing.T) {
+		c.setCategories(&ExploreCatTree{ID: 0, Children: []*ExploreCatTree{
+			{ID: 5, Children: []*ExploreCatTree{{ID: catProgramming}}},
+		}})
+		t.Cleanup(func() { c.setCategories(nil) })
+
+		assert.NoError(t, ValidateExploreCombo(catProgramming, "youtube"))
+		err := ValidateExploreCombo(999999, "youtube")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown category 999999 for YouTube search")
+	})
+
+	t.Run("strict explore", func(t *testing.T) {
+		req := &ExploreRequest{
+			ComparisonItems: []*ComparisonItem{{Keyword: "golang", Time: "today 12-m"}},
+			Property:        "maps",
+		}
+
+		_, err := Explore(context.Background(), req, langEN)
+		assert.NoError(t, err)
+
+		c.strict = true
+		t.Cleanup(func() { c.strict = false })
+
+		_, err = Explore(context.Background(), req, langEN)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), errInvalidRequest)
+	})
+
+	t.Run("strict explore unsupported pair", func(t *testing.T) {
+		c.strict = true
+		t.Cleanup(func() { c.strict = false })
+		calls = 0
+
+		_, err := Explore(context.Background(), &ExploreRequest{
+			ComparisonItems: []*ComparisonItem{{Keyword: "golang", Time: "today 12-m"}},
+			Category:        16,
+			Property:        "froogle",
+		}, langEN)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `category 16 is not supported for property "froogle"`)
+		assert.Zero(t, calls)
+	})
+}
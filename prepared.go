@@ -0,0 +1,146 @@
+package googletrends
+
+import (
+	"context"
+	"strings"
+)
+
+// PreparedWidget is an ExploreWidget whose data request has been marshaled once
+// so it can be fetched repeatedly without re-encoding the widget request.
+// It is intended for tight polling loops over the same keyword.
+//
+// A PreparedWidget captures the widget token at preparation time. Tokens are
+// short-lived: once Google reports ErrTokenExpired, run Explore again and
+// prepare the fresh widget. Changes made to the original widget after Prepare
+// are not reflected in the prepared request.
+//
+// A PreparedWidget is immutable and safe for concurrent use.
+type PreparedWidget struct {
+	widgetType WidgetType
+	token      string
+	req        []byte
+
+	// metrics are the metrics of a related widget request, in ranked list order.
+	metrics []string
+
+	// client fetches the widget; nil means the package-level client at fetch time.
+	client *gClient
+}
+
+// Prepare marshals the data request of w for repeated fetching.
+// The widget must be of type TIMESERIES, GEO_MAP, RELATED_QUERIES or RELATED_TOPICS.
+//
+// Returns ErrInvalidWidgetType for any other widget type and ErrMissingToken
+// if the widget has no valid token.
+//
+// Example:
+//
+//	widgets, _ := googletrends.Explore(ctx, request, "EN")
+//	prepared, err := googletrends.Prepare(widgets.GetWidgetsByType(googletrends.IntOverTimeWidgetID)[0])
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for range time.Tick(time.Minute) {
+//	    timeline, err := prepared.FetchTimeline(ctx, "EN")
+//	    if errors.Is(err, googletrends.ErrTokenExpired) {
+//	        break // re-run Explore and Prepare again
+//	    }
+//	    fmt.Println(len(timeline))
+//	}
+func Prepare(w *ExploreWidget) (*PreparedWidget, error) {
+	var (
+		t       WidgetType
+		req     []byte
+		metrics []string
+		err     error
+	)
+
+	switch {
+	case strings.HasPrefix(w.ID, string(IntOverTimeWidgetID)):
+		t = IntOverTimeWidgetID
+		req, err = timelineRequest(w)
+	case strings.HasPrefix(w.ID, string(IntOverRegionID)):
+		t = IntOverRegionID
+		req, err = geoRequest(w)
+	case strings.HasPrefix(w.ID, string(RelatedQueriesID)):
+		t = RelatedQueriesID
+		req, metrics, err = relatedRequest(w)
+	case strings.HasPrefix(w.ID, string(RelatedTopicsID)):
+		t = RelatedTopicsID
+		req, metrics, err = relatedRequest(w)
+	default:
+		return nil, ErrInvalidWidgetType
+	}
+
+	if !w.HasValidToken() {
+		return nil, ErrMissingToken
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &PreparedWidget{
+		widgetType: t,
+		token:      w.Token,
+		req:        req,
+		metrics:    append([]string(nil), metrics...),
+	}, nil
+}
+
+// Prepare is like the package-level Prepare, but the prepared widget is fetched with the client c.
+func (c *Client) Prepare(w *ExploreWidget) (*PreparedWidget, error) {
+	p, err := Prepare(w)
+	if err != nil {
+		return nil, err
+	}
+
+	p.client = c.gClient
+
+	return p, nil
+}
+
+// fetcher returns the client the prepared widget is fetched with.
+func (p *PreparedWidget) fetcher() *gClient {
+	if p.client != nil {
+		return p.client
+	}
+
+	return defaultClient()
+}
+
+// Type returns the type of the prepared widget.
+func (p *PreparedWidget) Type() WidgetType {
+	return p.widgetType
+}
+
+// FetchTimeline retrieves interest over time data like InterestOverTime.
+// Returns ErrInvalidWidgetType if the prepared widget is not a TIMESERIES widget.
+func (p *PreparedWidget) FetchTimeline(ctx context.Context, hl string) ([]*Timeline, error) {
+	if p.widgetType != IntOverTimeWidgetID {
+		return nil, ErrInvalidWidgetType
+	}
+
+	return p.fetcher().fetchTimeline(ctx, p.token, hl, p.req)
+}
+
+// FetchGeo retrieves interest by location data like InterestByLocation.
+// Returns ErrInvalidWidgetType if the prepared widget is not a GEO_MAP widget.
+func (p *PreparedWidget) FetchGeo(ctx context.Context, hl string) ([]*GeoMap, error) {
+	if p.widgetType != IntOverRegionID {
+		return nil, ErrInvalidWidgetType
+	}
+
+	return p.fetcher().fetchGeo(ctx, p.token, hl, p.req)
+}
+
+// FetchRelated retrieves related queries or topics like Related.
+// Returns ErrInvalidWidgetType if the prepared widget is not a RELATED_QUERIES
+// or RELATED_TOPICS widget.
+func (p *PreparedWidget) FetchRelated(ctx context.Context, hl string) ([]*RankedKeyword, error) {
+	if p.widgetType != RelatedQueriesID && p.widgetType != RelatedTopicsID {
+		return nil, ErrInvalidWidgetType
+	}
+
+	return p.fetcher().fetchRelated(ctx, p.token, hl, p.req, p.metrics)
+}
@@ -0,0 +1,150 @@
+package googletrends
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const timelineBody = `)]}',{"default":{"timelineData":[{"time":"1609459200","value":[10,20]}]}}`
+
+// newTimelineWidget returns a TIMESERIES widget comparing n keywords.
+func newTimelineWidget(n int) *ExploreWidget {
+	items := make([]*WidgetComparisonItem, 0, n)
+	for i := 0; i < n; i++ {
+		items = append(items, &WidgetComparisonItem{
+			Time: "2020-01-01 2020-12-31",
+			ComplexKeywordsRestriction: KeywordsRestriction{
+				Keyword: []*KeywordRestriction{{Type: "BROAD", Value: "golang"}},
+			},
+		})
+	}
+
+	return &ExploreWidget{
+		ID:    string(IntOverTimeWidgetID),
+		Token: testToken,
+		Request: &WidgetResponse{
+			Time:        "2020-01-01 2020-12-31",
+			Resolution:  "WEEK",
+			Locale:      "en-US",
+			CompItem:    items,
+			RequestOpt:  RequestOptions{Backend: "IZG", Category: catProgramming},
+			KeywordType: "QUERY",
+			Metric:      []string{metricTop, metricRising},
+			Language:    "en",
+		},
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	t.Run("fetches with the prepared request", func(t *testing.T) {
+		var sent []string
+		useMockClient(t, func(req *http.Request) (*http.Response, error) {
+			sent = append(sent, req.URL.Query().Get(paramReq))
+			return newMockResponse(http.StatusOK, timelineBody), nil
+		})
+
+		w := newTimelineWidget(2)
+		prepared, err := Prepare(w)
+		require.NoError(t, err)
+		assert.Equal(t, IntOverTimeWidgetID, prepared.Type())
+
+		// later widget changes don't leak into the prepared request
+		w.Request.Time = "today 5-y"
+
+		for i := 0; i < 2; i++ {
+			timeline, err := prepared.FetchTimeline(context.Background(), langEN)
+			require.NoError(t, err)
+			assert.Len(t, timeline, 1)
+		}
+
+		require.Len(t, sent, 2)
+		assert.Equal(t, sent[0], sent[1])
+		assert.Contains(t, sent[0], compareDataMode)
+		assert.NotContains(t, sent[0], "today 5-y")
+	})
+
+	t.Run("related widget keeps its metrics", func(t *testing.T) {
+		useMockClient(t, func(req *http.Request) (*http.Response, error) {
+			return newMockResponse(http.StatusOK, `)]}',{"default":{"rankedList":[`+
+				`{"rankedKeyword":[{"query":"golang 1.23","value":45050,"formattedValue":"Breakout"}]}]}}`), nil
+		})
+
+		w := &ExploreWidget{
+			ID:    string(RelatedQueriesID),
+			Token: testToken,
+			Request: &WidgetResponse{
+				Restriction: WidgetComparisonItem{Geo: map[string]string{"country": locUS}},
+				Metric:      []string{metricRising},
+			},
+		}
+		prepared, err := Prepare(w)
+		require.NoError(t, err)
+
+		// later widget changes don't leak into the prepared metrics
+		w.Request.Metric[0] = metricTop
+
+		related, err := prepared.FetchRelated(context.Background(), langEN)
+		require.NoError(t, err)
+		require.Len(t, related, 1)
+		assert.True(t, related[0].isRising())
+
+		// the single list of a top only request is not rising
+		related, err = Related(context.Background(), w, langEN, WithTopOnly())
+		require.NoError(t, err)
+		require.Len(t, related, 1)
+		assert.False(t, related[0].isRising())
+	})
+
+	t.Run("rejects mismatched fetches", func(t *testing.T) {
+		prepared, err := Prepare(newTimelineWidget(1))
+		require.NoError(t, err)
+
+		_, err = prepared.FetchGeo(context.Background(), langEN)
+		assert.ErrorIs(t, err, ErrInvalidWidgetType)
+
+		_, err = prepared.FetchRelated(context.Background(), langEN)
+		assert.ErrorIs(t, err, ErrInvalidWidgetType)
+	})
+
+	t.Run("rejects unknown widget types", func(t *testing.T) {
+		_, err := Prepare(&ExploreWidget{ID: "UNKNOWN", Request: &WidgetResponse{}})
+		assert.ErrorIs(t, err, ErrInvalidWidgetType)
+	})
+}
+
+func BenchmarkInterestOverTime(b *testing.B) {
+	useMockClient(b, func(req *http.Request) (*http.Response, error) {
+		return newMockResponse(http.StatusOK, timelineBody), nil
+	})
+	w := newTimelineWidget(maxComparisonItems)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := InterestOverTime(context.Background(), w, langEN); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPreparedWidgetFetchTimeline(b *testing.B) {
+	useMockClient(b, func(req *http.Request) (*http.Response, error) {
+		return newMockResponse(http.StatusOK, timelineBody), nil
+	})
+	prepared, err := Prepare(newTimelineWidget(maxComparisonItems))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := prepared.FetchTimeline(context.Background(), langEN); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package googletrends
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// RequestPreview describes an HTTP request exactly as the package would send it.
+// It is produced by the dry-run functions below, which build the request without
+// performing it: no HTTP call is made and no client state, such as the cookie
+// reused after rate limiting, is read or changed.
+//
+// The preview can be copied into curl to diagnose issues:
+//
+//	preview, _ := googletrends.ExplorePreview(request, "EN")
+//	fmt.Printf("curl -X %s '%s'\n", preview.Method, preview.URL)
+type RequestPreview struct {
+	// Method is the HTTP method, GET or POST.
+	Method string
+	// URL is the fully-constructed request URL including its query parameters.
+	URL *url.URL
+	// Payload is the request body; it is empty for GET requests.
+	Payload string
+}
+
+// ExplorePreview returns the request Explore would send for r.
+// Like Explore it leaves r unmodified.
+func ExplorePreview(r *ExploreRequest, hl string) (*RequestPreview, error) {
+	return std().ExplorePreview(r, hl)
+}
+
+// ExplorePreview is like the package-level ExplorePreview, using the client c.
+func (c *Client) ExplorePreview(r *ExploreRequest, hl string) (*RequestPreview, error) {
+	u, err := c.exploreURL(r, hl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RequestPreview{Method: http.MethodGet, URL: u}, nil
+}
+
+// WidgetPreview returns the request InterestOverTime, InterestByLocation or Related
+// would send for w, depending on the widget type.
+//
+// Returns ErrInvalidWidgetType for any other widget type.
+func WidgetPreview(w *ExploreWidget, hl string) (*RequestPreview, error) {
+	p, err := Prepare(w)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Preview(hl), nil
+}
+
+// Preview returns the request the Fetch method matching the widget type would send.
+func (p *PreparedWidget) Preview(hl string) *RequestPreview {
+	var path string
+	switch p.widgetType {
+	case IntOverTimeWidgetID:
+		path = gSIntOverTime
+	case IntOverRegionID:
+		path = gSIntOverReg
+	default:
+		path = gSRelated
+	}
+
+	return &RequestPreview{Method: http.MethodGet, URL: p.fetcher().widgetURL(path, p.token, hl, p.req)}
+}
+
+// DailyNewPreview returns the request DailyNew and DailyTrendingSearchNew would send.
+func DailyNewPreview(hl, loc string) *RequestPreview {
+	return std().DailyNewPreview(hl, loc)
+}
+
+// DailyNewPreview is like the package-level DailyNewPreview, using the client c.
+func (c *Client) DailyNewPreview(hl, loc string) *RequestPreview {
+	return &RequestPreview{Method: http.MethodPost, URL: trendsNewURL(hl), Payload: trendsNewPayload(c.batchRPCID, loc, 0)}
+}
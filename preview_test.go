@@ -0,0 +1,90 @@
+package googletrends
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreview(t *testing.T) {
+	var sent []*http.Request
+	var payloads []string
+	c := useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		var payload string
+		if req.Body != nil {
+			b, _ := io.ReadAll(req.Body)
+			payload = string(b)
+		}
+		sent = append(sent, req)
+		payloads = append(payloads, payload)
+
+		switch req.URL.Path {
+		case "/trends/api/explore":
+			return newMockResponse(http.StatusOK, `)]}'{"widgets":[]}`), nil
+		case "/_/TrendsUi/data/batchexecute":
+			return newMockResponse(http.StatusOK, newBatchResponse(t, []interface{}{"golang"})), nil
+		}
+		return newMockResponse(http.StatusOK, timelineBody), nil
+	})
+	c.setCookie("NID=1")
+
+	ctx := context.Background()
+
+	t.Run("explore", func(t *testing.T) {
+		sent, payloads = nil, nil
+		req := &ExploreRequest{
+			ComparisonItems: []*ComparisonItem{{Keyword: "golang", Geo: "usa", Time: "today+12-m"}},
+		}
+
+		preview, err := ExplorePreview(req, langEN)
+		require.NoError(t, err)
+		assert.Empty(t, sent)
+
+		_, err = Explore(ctx, req, langEN)
+		require.NoError(t, err)
+		require.Len(t, sent, 1)
+		assert.Equal(t, http.MethodGet, preview.Method)
+		assert.Equal(t, sent[0].URL.String(), preview.URL.String())
+		assert.Empty(t, preview.Payload)
+		assert.Contains(t, preview.URL.Query().Get(paramReq), `"geo":"US"`)
+	})
+
+	t.Run("widget", func(t *testing.T) {
+		sent, payloads = nil, nil
+		w := newTimelineWidget(1)
+
+		preview, err := WidgetPreview(w, langEN)
+		require.NoError(t, err)
+		assert.Empty(t, sent)
+
+		_, err = InterestOverTime(ctx, w, langEN)
+		require.NoError(t, err)
+		require.Len(t, sent, 1)
+		assert.Equal(t, http.MethodGet, preview.Method)
+		assert.Equal(t, sent[0].URL.String(), preview.URL.String())
+	})
+
+	t.Run("invalid widget", func(t *testing.T) {
+		_, err := WidgetPreview(&ExploreWidget{ID: "unknown"}, langEN)
+		assert.ErrorIs(t, err, ErrInvalidWidgetType)
+	})
+
+	t.Run("daily new", func(t *testing.T) {
+		sent, payloads = nil, nil
+		preview := DailyNewPreview(langEN, "usa")
+		assert.Empty(t, sent)
+
+		_, err := DailyNew(ctx, langEN, "usa")
+		require.NoError(t, err)
+		require.Len(t, sent, 1)
+		assert.Equal(t, http.MethodPost, preview.Method)
+		assert.Equal(t, sent[0].URL.String(), preview.URL.String())
+		assert.Equal(t, payloads[0], preview.Payload)
+	})
+
+	assert.Equal(t, "NID=1", c.getCookie())
+}
@@ -0,0 +1,480 @@
+package googletrends
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// maxExpandKeywords bounds the number of keywords ExpandRelated explores.
+// Each explored keyword costs two requests (Explore and Related).
+const maxExpandKeywords = 25
+
+// ExpandRelated builds a map of related topics starting from a seed keyword.
+// It explores the seed, fetches its related topics, then repeats the process for
+// every related topic until depth levels have been expanded.
+//
+// The result maps each explored keyword to its related topics. Related topics are
+// recursed on by their title; keywords are de-duplicated case-insensitively, so
+// cycles (e.g. "Go" -> "Docker" -> "Go") are explored only once. To protect against
+// a request explosion, at most 25 keywords are explored in total; remaining
+// keywords at the last reached level are left out of the map.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - seed: The keyword to start from
+//   - depth: Number of levels to expand (1 returns only the seed's related topics)
+//   - geo: Geographic location code (e.g., "US"), empty for worldwide
+//   - timeRange: Time range for the explore requests (e.g., "today 12-m")
+//   - hl: Host language code (e.g., "EN", "RU")
+//
+// Example:
+//
+//	topics, err := googletrends.ExpandRelated(ctx, "golang", 2, "US", "today 12-m", "EN")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for keyword, related := range topics {
+//	    fmt.Println(keyword, len(related))
+//	}
+func ExpandRelated(ctx context.Context, seed string, depth int, geo, timeRange, hl string) (map[string][]*RankedKeyword, error) {
+	return std().ExpandRelated(ctx, seed, depth, geo, timeRange, hl)
+}
+
+// ExpandRelated is like the package-level ExpandRelated, using the client c.
+func (c *Client) ExpandRelated(ctx context.Context, seed string, depth int, geo, timeRange, hl string) (map[string][]*RankedKeyword, error) {
+	return c.expandRelated(ctx, seed, depth, geo, timeRange, hl, maxExpandKeywords)
+}
+
+// expandRelated implements ExpandRelated with a configurable keyword limit.
+func (c *Client) expandRelated(ctx context.Context, seed string, depth int, geo, timeRange, hl string, limit int) (map[string][]*RankedKeyword, error) {
+	if len(strings.TrimSpace(seed)) == 0 {
+		return nil, fmt.Errorf("%s: empty seed keyword", errInvalidRequest)
+	}
+	if depth < 1 {
+		return nil, fmt.Errorf("%s: depth must be positive, got %d", errInvalidRequest, depth)
+	}
+
+	out := make(map[string][]*RankedKeyword)
+	visited := map[string]struct{}{strings.ToLower(seed): {}}
+	level := []string{seed}
+
+	for d := 0; d < depth && len(level) > 0; d++ {
+		next := make([]string, 0)
+		for _, keyword := range level {
+			if len(out) >= limit {
+				return out, nil
+			}
+
+			related, err := c.relatedTopics(ctx, keyword, geo, timeRange, hl)
+			if err != nil {
+				return nil, fmt.Errorf("expand %q: %w", keyword, err)
+			}
+			out[keyword] = related
+
+			for _, v := range related {
+				term := v.term()
+				if len(term) == 0 {
+					continue
+				}
+
+				key := strings.ToLower(term)
+				if _, ok := visited[key]; ok {
+					continue
+				}
+				visited[key] = struct{}{}
+				next = append(next, term)
+			}
+		}
+		level = next
+	}
+
+	return out, nil
+}
+
+// relatedTopics explores a single keyword and fetches its related topics.
+// It returns an empty slice if Google returns no related topics widget.
+func (c *Client) relatedTopics(ctx context.Context, keyword, geo, timeRange, hl string) ([]*RankedKeyword, error) {
+	widgets, err := c.Explore(ctx, &ExploreRequest{
+		ComparisonItems: []*ComparisonItem{
+			{Keyword: keyword, Geo: geo, Time: timeRange},
+		},
+	}, hl)
+	if err != nil {
+		return nil, err
+	}
+
+	topics := widgets.GetWidgetsByType(RelatedTopicsID)
+	if len(topics) == 0 {
+		return []*RankedKeyword{}, nil
+	}
+
+	return c.Related(ctx, topics[0], hl)
+}
+
+// widgetOrder returns the comparison item index encoded in a widget ID suffix
+// (e.g. 1 for "RELATED_QUERIES_1"). Widgets without an index suffix belong to
+// a single-keyword explore and get index 0.
+func widgetOrder(id string) int {
+	ind := strings.LastIndex(id, "_")
+	if ind < 0 {
+		return 0
+	}
+
+	val, err := strconv.Atoi(id[ind+1:])
+	if err != nil {
+		return 0
+	}
+
+	return val
+}
+
+// AllRelatedQueries fetches the related queries of every keyword in a comparison.
+// It requests every RELATED_QUERIES widget of the response concurrently and returns
+// the results keyed by the keyword order index (the N in "RELATED_QUERIES_N").
+//
+// All widgets are fetched even if some fail. The returned error joins the errors of
+// the failed widgets, and the map still holds the results of the successful ones.
+//
+// Example:
+//
+//	widgets, _ := googletrends.Explore(ctx, compareRequest, "EN")
+//	queries, err := googletrends.AllRelatedQueries(ctx, widgets, "EN")
+//	if err != nil {
+//	    log.Println(err)
+//	}
+//	for i, item := range compareRequest.ComparisonItems {
+//	    fmt.Println(item.Keyword, len(queries[i]))
+//	}
+func AllRelatedQueries(ctx context.Context, widgets ExploreResponse, hl string) (map[int][]*RankedKeyword, error) {
+	return std().AllRelatedQueries(ctx, widgets, hl)
+}
+
+// AllRelatedQueries is like the package-level AllRelatedQueries, using the client c.
+func (c *Client) AllRelatedQueries(ctx context.Context, widgets ExploreResponse, hl string) (map[int][]*RankedKeyword, error) {
+	queries := widgets.GetWidgetsByType(RelatedQueriesID)
+
+	out := make(map[int][]*RankedKeyword, len(queries))
+	mu := new(sync.Mutex)
+	err := forEachConcurrent(ctx, len(queries), maxConcurrentRequests, func(ctx context.Context, i int) error {
+		w := queries[i]
+		keywords, err := c.Related(ctx, w, hl)
+		if err != nil {
+			return fmt.Errorf("%s: %w", w.ID, err)
+		}
+
+		mu.Lock()
+		out[widgetOrder(w.ID)] = keywords
+		mu.Unlock()
+
+		return nil
+	})
+
+	return out, err
+}
+
+// FilterByMinValue returns the keywords whose Value is at least min, keeping their order.
+// Use it to drop low-signal related queries or topics in one call.
+//
+// Rising entries are always kept: their FormattedValue is a percentage like "+250%"
+// or "Breakout" (localized in other languages), and their Value is a growth figure,
+// with a huge sentinel for breakouts, that isn't comparable to the 0-100 interest
+// scale of top entries. Entries of the RISING list of a related widget and entries
+// formatted as "+N%" are treated as rising; other entries, including top values
+// shown as "<1", are compared by Value. Nil entries are skipped.
+//
+// Example:
+//
+//	related, _ := googletrends.Related(ctx, widget, "EN")
+//	strong := googletrends.FilterByMinValue(related, 20)
+func FilterByMinValue(keywords []*RankedKeyword, min int) []*RankedKeyword {
+	out := make([]*RankedKeyword, 0, len(keywords))
+	for _, k := range keywords {
+		if k == nil {
+			continue
+		}
+
+		if k.isRising() || k.Value >= min {
+			out = append(out, k)
+		}
+	}
+
+	return out
+}
+
+// DiffRelated compares two snapshots of related queries or topics, e.g. from Related
+// calls a week apart. added holds the entries of next missing from prev, removed the
+// entries of prev missing from next, both in their original order.
+//
+// Entries are matched by their query for related queries or by their topic title
+// for related topics, whichever is set. Values are not compared, and nil entries
+// are skipped.
+//
+// Example:
+//
+//	added, _ := googletrends.DiffRelated(lastWeek, thisWeek)
+//	for _, k := range added {
+//	    fmt.Println("new related search:", k.Query)
+//	}
+func DiffRelated(prev, next []*RankedKeyword) (added, removed []*RankedKeyword) {
+	return missingTerms(next, prev), missingTerms(prev, next)
+}
+
+// missingTerms returns the keywords of from whose term is missing from other.
+func missingTerms(from, other []*RankedKeyword) []*RankedKeyword {
+	seen := make(map[string]struct{}, len(other))
+	for _, k := range other {
+		if k != nil {
+			seen[k.term()] = struct{}{}
+		}
+	}
+
+	out := make([]*RankedKeyword, 0)
+	for _, k := range from {
+		if k == nil {
+			continue
+		}
+
+		if _, ok := seen[k.term()]; !ok {
+			out = append(out, k)
+		}
+	}
+
+	return out
+}
+
+// MergeKeywordSet flattens several related queries or topics results, e.g. from
+// Related calls on different seeds, into a sorted set of unique keywords.
+//
+// Each entry contributes its query for related queries or its topic title for
+// related topics. Keywords are lowercased and trimmed before de-duplication;
+// nil entries and entries without a query or title are skipped.
+//
+// Example:
+//
+//	queries, _ := googletrends.Related(ctx, queriesWidget, "EN")
+//	topics, _ := googletrends.Related(ctx, topicsWidget, "EN")
+//	for _, keyword := range googletrends.MergeKeywordSet(queries, topics) {
+//	    fmt.Println(keyword)
+//	}
+func MergeKeywordSet(lists ...[]*RankedKeyword) []string {
+	seen := make(map[string]struct{})
+	out := make([]string, 0)
+	for _, list := range lists {
+		for _, k := range list {
+			if k == nil {
+				continue
+			}
+
+			keyword := strings.ToLower(strings.TrimSpace(k.term()))
+			if len(keyword) == 0 {
+				continue
+			}
+
+			if _, ok := seen[keyword]; ok {
+				continue
+			}
+			seen[keyword] = struct{}{}
+			out = append(out, keyword)
+		}
+	}
+
+	sort.Strings(out)
+
+	return out
+}
+
+// ToExploreRequest builds the explore request behind the Link of a related query or
+// topic, to re-run the exact comparison it points to with Explore.
+//
+// The link's q parameter holds the keywords and date, geo and cat their time range,
+// location and category; comma-separated values are matched to the keywords in
+// order, and a single value applies to every keyword. A missing date defaults to
+// "today 12-m" like in the web UI, a missing geo to worldwide and a missing cat to
+// all categories. The gprop parameter, if any, sets the Property.
+//
+// Returns an error if the link has no q parameter or can't be parsed.
+//
+// Example:
+//
+//	related, _ := googletrends.Related(ctx, widget, "EN")
+//	req, err := related[0].ToExploreRequest()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	widgets, err := googletrends.Explore(ctx, req, "EN")
+func (k *RankedKeyword) ToExploreRequest() (*ExploreRequest, error) {
+	u, err := url.Parse(k.Link)
+	if err != nil {
+		return nil, fmt.Errorf("%s: explore link %q: %w", errInvalidRequest, k.Link, err)
+	}
+	p := u.Query()
+
+	if len(strings.TrimSpace(p.Get(paramQuery))) == 0 {
+		return nil, fmt.Errorf("%s: explore link %q has no query", errInvalidRequest, k.Link)
+	}
+	keywords := strings.Split(p.Get(paramQuery), ",")
+	dates := strings.Split(p.Get(paramDate), ",")
+	geos := strings.Split(p.Get(paramGeo), ",")
+
+	r := &ExploreRequest{
+		ComparisonItems: make([]*ComparisonItem, 0, len(keywords)),
+		Property:        p.Get(paramProperty),
+	}
+
+	if cat := p.Get(paramCat); len(cat) != 0 {
+		if r.Category, err = strconv.Atoi(cat); err != nil {
+			return nil, fmt.Errorf("%s: explore link category %q: %w", errInvalidRequest, cat, err)
+		}
+	}
+
+	for i, keyword := range keywords {
+		item := &ComparisonItem{
+			Keyword: strings.TrimSpace(keyword),
+			Geo:     linkValue(geos, i),
+			Time:    linkValue(dates, i),
+		}
+		if len(item.Time) == 0 {
+			item.Time = defaultExploreTime
+		}
+
+		r.ComparisonItems = append(r.ComparisonItems, item)
+	}
+
+	return r, nil
+}
+
+// linkValue returns the explore link value of the keyword at index i
+// from a comma-separated parameter, where a single value applies to every keyword.
+func linkValue(values []string, i int) string {
+	if len(values) == 1 {
+		return strings.TrimSpace(values[0])
+	}
+	if i < len(values) {
+		return strings.TrimSpace(values[i])
+	}
+
+	return ""
+}
+
+// RankedSet holds the related queries or topics of one keyword split into rankings.
+type RankedSet struct {
+	// Top holds the most popular entries, valued on the 0-100 interest scale.
+	Top []*RankedKeyword `json:"top" bson:"top"`
+
+	// Rising holds the entries with the biggest growth, valued as a percentage.
+	Rising []*RankedKeyword `json:"rising" bson:"rising"`
+}
+
+// RelatedResult holds both related widgets of one keyword, as returned by RelatedFull.
+type RelatedResult struct {
+	// Topics holds the related topics of the keyword.
+	Topics RankedSet `json:"topics" bson:"topics"`
+
+	// Queries holds the related queries of the keyword.
+	Queries RankedSet `json:"queries" bson:"queries"`
+}
+
+// RelatedFull fetches the related topics and queries of the keyword at order in a
+// comparison (0 for a single-keyword explore) and returns them split into TOP and
+// RISING rankings. Both widgets are requested concurrently.
+//
+// A widget missing from widgets, e.g. because Google omitted it for a low-volume
+// keyword, leaves its set empty. Both widgets are fetched even if one fails; the
+// returned error joins their errors, and the result still holds the successful one.
+//
+// Example:
+//
+//	widgets, _ := googletrends.Explore(ctx, request, "EN")
+//	related, err := googletrends.RelatedFull(ctx, widgets, 0, "EN")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, k := range related.Queries.Rising {
+//	    fmt.Println(k.Query, k.FormattedValue)
+//	}
+func RelatedFull(ctx context.Context, widgets ExploreResponse, order int, hl string) (*RelatedResult, error) {
+	return std().RelatedFull(ctx, widgets, order, hl)
+}
+
+// RelatedFull is like the package-level RelatedFull, using the client c.
+func (c *Client) RelatedFull(ctx context.Context, widgets ExploreResponse, order int, hl string) (*RelatedResult, error) {
+	out := &RelatedResult{
+		Topics:  RankedSet{Top: []*RankedKeyword{}, Rising: []*RankedKeyword{}},
+		Queries: RankedSet{Top: []*RankedKeyword{}, Rising: []*RankedKeyword{}},
+	}
+	targets := map[WidgetType]*RankedSet{
+		RelatedTopicsID:  &out.Topics,
+		RelatedQueriesID: &out.Queries,
+	}
+
+	found := make([]*ExploreWidget, 0, len(targets))
+	sets := make([]*RankedSet, 0, len(targets))
+	for _, id := range []WidgetType{RelatedTopicsID, RelatedQueriesID} {
+		for _, w := range widgets.GetWidgetsByType(id) {
+			if widgetOrder(w.ID) == order {
+				found = append(found, w)
+				sets = append(sets, targets[id])
+				break
+			}
+		}
+	}
+
+	err := forEachConcurrent(ctx, len(found), maxConcurrentRequests, func(ctx context.Context, i int) error {
+		w := found[i]
+		if !w.HasValidToken() {
+			return fmt.Errorf("%s: %w", w.ID, ErrMissingToken)
+		}
+
+		reqBytes, metrics, err := relatedRequest(w)
+		if err != nil {
+			return fmt.Errorf("%s: %w", w.ID, err)
+		}
+
+		lists, err := c.fetchRankedLists(ctx, w.Token, hl, reqBytes, metrics)
+		if err != nil {
+			return fmt.Errorf("%s: %w", w.ID, err)
+		}
+
+		// every set is written by a single goroutine only
+		splitRanked(sets[i], lists, metrics)
+
+		return nil
+	})
+
+	return out, err
+}
+
+// rankedMetric returns the metric of the ranked list at index i of a related widget
+// requesting metrics. Google sends TOP before RISING when metrics are not given.
+func rankedMetric(i int, metrics []string) string {
+	if i < len(metrics) {
+		return metrics[i]
+	}
+	if i > 0 {
+		return metricRising
+	}
+
+	return metricTop
+}
+
+// splitRanked fills set from the ranked lists of a related widget, where the list
+// at index i is the ranking of metrics[i]. Google sends TOP before RISING.
+func splitRanked(set *RankedSet, lists []*rankedList, metrics []string) {
+	for i, list := range lists {
+		if list == nil {
+			continue
+		}
+
+		switch rankedMetric(i, metrics) {
+		case metricTop:
+			set.Top = append(set.Top, list.Keywords...)
+		case metricRising:
+			set.Rising = append(set.Rising, list.Keywords...)
+		}
+	}
+}
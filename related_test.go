@@ -0,0 +1,438 @@
+package googletrends
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRelatedGraph returns a mock transport serving Explore and related topics
+// requests from graph, where each keyword maps to the titles of its related topics.
+// Every explored keyword is recorded in explored.
+func fakeRelatedGraph(graph map[string][]string, explored *[]string) func(req *http.Request) (*http.Response, error) {
+	mu := new(sync.Mutex)
+	return func(req *http.Request) (*http.Response, error) {
+		switch {
+		case strings.HasSuffix(req.URL.Path, gSExplore):
+			r := new(ExploreRequest)
+			if err := json.Unmarshal([]byte(req.URL.Query().Get(paramReq)), r); err != nil {
+				return nil, err
+			}
+			keyword := r.ComparisonItems[0].Keyword
+
+			mu.Lock()
+			*explored = append(*explored, keyword)
+			mu.Unlock()
+
+			body := fmt.Sprintf(`)]}'{"widgets":[{"id":"RELATED_TOPICS","token":%q,"request":{"restriction":{"geo":{"country":"US"}}}}]}`,
+				testToken+url.QueryEscape(keyword))
+			return newMockResponse(http.StatusOK, body), nil
+		case strings.HasSuffix(req.URL.Path, gSRelated):
+			keyword, _ := url.QueryUnescape(strings.TrimPrefix(req.URL.Query().Get(paramToken), testToken))
+
+			ranked := make([]*RankedKeyword, 0)
+			for _, title := range graph[keyword] {
+				ranked = append(ranked, &RankedKeyword{Topic: KeywordTopic{Title: title}, Value: 100})
+			}
+			b, _ := json.Marshal(relatedOut{Default: &relatedList{Ranked: []*rankedList{{Keywords: ranked}}}})
+
+			return newMockResponse(http.StatusOK, ")]}',"+string(b)), nil
+		}
+
+		return newMockResponse(http.StatusNotFound, ""), nil
+	}
+}
+
+func TestExpandRelated(t *testing.T) {
+	graph := map[string][]string{
+		"golang":     {"Docker", "Kubernetes"},
+		"Docker":     {"Golang", "Containers"},
+		"Kubernetes": {"Docker", "Helm"},
+		"Containers": {"Docker"},
+		"Helm":       {"Kubernetes"},
+	}
+
+	t.Run("depth one returns only seed", func(t *testing.T) {
+		var explored []string
+		useMockClient(t, fakeRelatedGraph(graph, &explored))
+
+		out, err := ExpandRelated(context.Background(), "golang", 1, locUS, "today 12-m", langEN)
+
+		require.NoError(t, err)
+		assert.Len(t, out, 1)
+		assert.Len(t, out["golang"], 2)
+		assert.Equal(t, []string{"golang"}, explored)
+	})
+
+	t.Run("recursion skips visited keywords", func(t *testing.T) {
+		var explored []string
+		useMockClient(t, fakeRelatedGraph(graph, &explored))
+
+		out, err := ExpandRelated(context.Background(), "golang", 3, locUS, "today 12-m", langEN)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"golang", "Docker", "Kubernetes", "Containers", "Helm"}, explored)
+		assert.Len(t, out, 5)
+		assert.Equal(t, "Kubernetes", out["Helm"][0].Topic.Title)
+	})
+
+	t.Run("total keywords are bounded", func(t *testing.T) {
+		var explored []string
+		useMockClient(t, fakeRelatedGraph(graph, &explored))
+
+		out, err := std().expandRelated(context.Background(), "golang", 3, locUS, "today 12-m", langEN, 2)
+
+		require.NoError(t, err)
+		assert.Len(t, out, 2)
+		assert.Equal(t, []string{"golang", "Docker"}, explored)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		_, err := ExpandRelated(context.Background(), "golang", 0, locUS, "today 12-m", langEN)
+		assert.Error(t, err)
+
+		_, err = ExpandRelated(context.Background(), " ", 1, locUS, "today 12-m", langEN)
+		assert.Error(t, err)
+	})
+
+	t.Run("request error is returned", func(t *testing.T) {
+		useMockClient(t, func(req *http.Request) (*http.Response, error) {
+			return newMockResponse(http.StatusInternalServerError, ""), nil
+		})
+
+		out, err := ExpandRelated(context.Background(), "golang", 2, locUS, "today 12-m", langEN)
+
+		assert.Nil(t, out)
+		assert.ErrorIs(t, err, ErrRequestFailed)
+	})
+}
+
+func TestAllRelatedQueries(t *testing.T) {
+	useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		token := strings.TrimPrefix(req.URL.Query().Get(paramToken), testToken)
+		if token == "broken" {
+			return newMockResponse(http.StatusInternalServerError, ""), nil
+		}
+		return newMockResponse(http.StatusOK,
+			fmt.Sprintf(`)]}',{"default":{"rankedList":[{"rankedKeyword":[{"query":%q,"value":100}]}]}}`, token)), nil
+	})
+
+	newWidget := func(id, token string) *ExploreWidget {
+		return &ExploreWidget{
+			ID:      id,
+			Token:   testToken + token,
+			Request: &WidgetResponse{Restriction: WidgetComparisonItem{Geo: map[string]string{"country": locUS}}},
+		}
+	}
+
+	t.Run("keyed by keyword order", func(t *testing.T) {
+		widgets := ExploreResponse{
+			{ID: string(IntOverTimeWidgetID)},
+			newWidget("RELATED_QUERIES_0", "golang"),
+			newWidget("RELATED_TOPICS_0", "topic"),
+			newWidget("RELATED_QUERIES_1", "python"),
+			newWidget("RELATED_QUERIES_2", "rust"),
+		}
+
+		out, err := AllRelatedQueries(context.Background(), widgets, langEN)
+
+		require.NoError(t, err)
+		require.Len(t, out, 3)
+		assert.Equal(t, "golang", out[0][0].Query)
+		assert.Equal(t, "python", out[1][0].Query)
+		assert.Equal(t, "rust", out[2][0].Query)
+	})
+
+	t.Run("single keyword widget", func(t *testing.T) {
+		out, err := AllRelatedQueries(context.Background(), ExploreResponse{newWidget("RELATED_QUERIES", "golang")}, langEN)
+
+		require.NoError(t, err)
+		assert.Equal(t, "golang", out[0][0].Query)
+	})
+
+	t.Run("errors are aggregated", func(t *testing.T) {
+		widgets := ExploreResponse{
+			newWidget("RELATED_QUERIES_0", "golang"),
+			newWidget("RELATED_QUERIES_1", "broken"),
+		}
+
+		out, err := AllRelatedQueries(context.Background(), widgets, langEN)
+
+		assert.ErrorIs(t, err, ErrRequestFailed)
+		assert.Contains(t, err.Error(), "RELATED_QUERIES_1")
+		assert.Len(t, out, 1)
+		assert.Equal(t, "golang", out[0][0].Query)
+	})
+}
+
+func TestFilterByMinValue(t *testing.T) {
+	t.Parallel()
+
+	top := &RankedKeyword{Query: "golang tutorial", Value: 100, FormattedValue: "100"}
+	low := &RankedKeyword{Query: "golang logo", Value: 5, FormattedValue: "5"}
+	edge := &RankedKeyword{Query: "golang jobs", Value: 20, FormattedValue: "20"}
+	rising := &RankedKeyword{Query: "golang 1.23", Value: 10, FormattedValue: "+10%"}
+	breakout := &RankedKeyword{Query: "golang iterators", Value: 45050, FormattedValue: "Breakout", rising: true}
+	unformatted := &RankedKeyword{Query: "golang wasm", Value: 3}
+	belowOne := &RankedKeyword{Query: "golang mascot", Value: 0, FormattedValue: "<1"}
+	thousands := &RankedKeyword{Query: "golang conference", Value: 1000, FormattedValue: "1,000"}
+
+	keywords := []*RankedKeyword{top, low, nil, rising, edge, breakout, unformatted, belowOne, thousands}
+
+	tests := []struct {
+		name     string
+		min      int
+		expected []*RankedKeyword
+	}{
+		{
+			name:     "drops low values and keeps rising",
+			min:      20,
+			expected: []*RankedKeyword{top, rising, edge, breakout, thousands},
+		},
+		{
+			name:     "zero keeps all",
+			min:      0,
+			expected: []*RankedKeyword{top, low, rising, edge, breakout, unformatted, belowOne, thousands},
+		},
+		{
+			name:     "formatted top values are compared by value",
+			min:      1001,
+			expected: []*RankedKeyword{rising, breakout},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FilterByMinValue(keywords, tt.min))
+		})
+	}
+
+	assert.Empty(t, FilterByMinValue(nil, 10))
+}
+
+func TestDiffRelated(t *testing.T) {
+	t.Parallel()
+
+	tutorial := &RankedKeyword{Query: "golang tutorial", Value: 100}
+	jobs := &RankedKeyword{Query: "golang jobs", Value: 40}
+	generics := &RankedKeyword{Query: "golang generics", Value: 30}
+	gopher := &RankedKeyword{Topic: KeywordTopic{Mid: "/m/01", Title: "Gopher"}, Value: 100}
+	google := &RankedKeyword{Topic: KeywordTopic{Mid: "/m/02", Title: "Google"}, Value: 80}
+	rust := &RankedKeyword{Topic: KeywordTopic{Mid: "/m/03", Title: "Rust"}, Value: 20}
+
+	tests := []struct {
+		name            string
+		prev            []*RankedKeyword
+		next            []*RankedKeyword
+		expectedAdded   []*RankedKeyword
+		expectedRemoved []*RankedKeyword
+	}{
+		{
+			name: "queries",
+			prev: []*RankedKeyword{tutorial, jobs},
+			// the value change of tutorial is not a difference
+			next:            []*RankedKeyword{{Query: "golang tutorial", Value: 60}, nil, generics},
+			expectedAdded:   []*RankedKeyword{generics},
+			expectedRemoved: []*RankedKeyword{jobs},
+		},
+		{
+			name:            "topics",
+			prev:            []*RankedKeyword{gopher, google},
+			next:            []*RankedKeyword{rust, gopher},
+			expectedAdded:   []*RankedKeyword{rust},
+			expectedRemoved: []*RankedKeyword{google},
+		},
+		{
+			name:            "empty previous snapshot",
+			prev:            nil,
+			next:            []*RankedKeyword{tutorial},
+			expectedAdded:   []*RankedKeyword{tutorial},
+			expectedRemoved: []*RankedKeyword{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, removed := DiffRelated(tt.prev, tt.next)
+
+			assert.Equal(t, tt.expectedAdded, added)
+			assert.Equal(t, tt.expectedRemoved, removed)
+		})
+	}
+}
+
+func TestMergeKeywordSet(t *testing.T) {
+	t.Parallel()
+
+	queries := []*RankedKeyword{
+		{Query: "golang tutorial", Value: 100},
+		{Query: "Golang Jobs", Value: 40},
+		nil,
+		{Query: "  ", Value: 10},
+	}
+	topics := []*RankedKeyword{
+		{Topic: KeywordTopic{Mid: "/m/01", Title: "Gopher"}, Value: 100},
+		{Topic: KeywordTopic{Mid: "/m/02"}, Value: 80},
+	}
+	more := []*RankedKeyword{
+		{Query: "golang jobs", Value: 70},
+		{Topic: KeywordTopic{Mid: "/m/01", Title: "gopher"}, Value: 20},
+		{Query: "docker", Value: 5},
+	}
+
+	assert.Equal(t, []string{"docker", "golang jobs", "golang tutorial", "gopher"}, MergeKeywordSet(queries, topics, more))
+	assert.Empty(t, MergeKeywordSet())
+	assert.Empty(t, MergeKeywordSet(nil, []*RankedKeyword{nil}))
+}
+
+func TestRankedKeywordToExploreRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		link     string
+		expected *ExploreRequest
+	}{
+		{
+			name: "related query",
+			link: "/trends/explore?q=golang+tutorial&date=today+5-y&geo=US&cat=31",
+			expected: &ExploreRequest{
+				ComparisonItems: []*ComparisonItem{{Keyword: "golang tutorial", Geo: "US", Time: "today 5-y"}},
+				Category:        catProgramming,
+			},
+		},
+		{
+			name: "related topic with defaults",
+			link: "/trends/explore?q=/m/09gbxjr",
+			expected: &ExploreRequest{
+				ComparisonItems: []*ComparisonItem{{Keyword: "/m/09gbxjr", Time: defaultExploreTime}},
+			},
+		},
+		{
+			name: "comparison with shared date",
+			link: "https://trends.google.com/trends/explore?q=golang,rust&date=now+7-d&geo=GB,DE&gprop=youtube",
+			expected: &ExploreRequest{
+				ComparisonItems: []*ComparisonItem{
+					{Keyword: "golang", Geo: "GB", Time: "now 7-d"},
+					{Keyword: "rust", Geo: "DE", Time: "now 7-d"},
+				},
+				Property: "youtube",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := (&RankedKeyword{Link: tt.link}).ToExploreRequest()
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, r)
+		})
+	}
+
+	for name, link := range map[string]string{
+		"empty link":  "",
+		"no query":    "/trends/explore?geo=US",
+		"bad cat":     "/trends/explore?q=golang&cat=programming",
+		"broken link": "%zz",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := (&RankedKeyword{Link: link}).ToExploreRequest()
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), errInvalidRequest)
+		})
+	}
+}
+
+func TestRelatedFull(t *testing.T) {
+	useMockClient(t, func(req *http.Request) (*http.Response, error) {
+		token := strings.TrimPrefix(req.URL.Query().Get(paramToken), testToken)
+		switch token {
+		case "broken":
+			return newMockResponse(http.StatusInternalServerError, ""), nil
+		case "topics":
+			return newMockResponse(http.StatusOK, `)]}',{"default":{"rankedList":[`+
+				`{"rankedKeyword":[{"topic":{"mid":"/m/01","title":"Gopher"},"value":100,"formattedValue":"100"}]},`+
+				`{"rankedKeyword":[{"topic":{"mid":"/m/02","title":"Generics"},"value":250,"formattedValue":"+250%"}]}]}}`), nil
+		}
+		return newMockResponse(http.StatusOK, fmt.Sprintf(`)]}',{"default":{"rankedList":[`+
+			`{"rankedKeyword":[{"query":"%s tutorial","value":100,"formattedValue":"100"},{"query":"%s jobs","value":40,"formattedValue":"40"}]},`+
+			`{"rankedKeyword":[{"query":"%s 1.23","value":45050,"formattedValue":"Breakout"}]}]}}`, token, token, token)), nil
+	})
+
+	newWidget := func(id, token string) *ExploreWidget {
+		return &ExploreWidget{
+			ID:    id,
+			Token: testToken + token,
+			Request: &WidgetResponse{
+				Restriction: WidgetComparisonItem{Geo: map[string]string{"country": locUS}},
+				Metric:      []string{metricTop, metricRising},
+			},
+		}
+	}
+
+	t.Run("splits both widgets of the keyword", func(t *testing.T) {
+		widgets := ExploreResponse{
+			{ID: string(IntOverTimeWidgetID)},
+			newWidget("RELATED_TOPICS_0", "broken"),
+			newWidget("RELATED_QUERIES_0", "broken"),
+			newWidget("RELATED_TOPICS_1", "topics"),
+			newWidget("RELATED_QUERIES_1", "golang"),
+		}
+
+		out, err := RelatedFull(context.Background(), widgets, 1, langEN)
+
+		require.NoError(t, err)
+		require.Len(t, out.Topics.Top, 1)
+		assert.Equal(t, "Gopher", out.Topics.Top[0].Topic.Title)
+		require.Len(t, out.Topics.Rising, 1)
+		assert.Equal(t, "Generics", out.Topics.Rising[0].Topic.Title)
+		require.Len(t, out.Queries.Top, 2)
+		assert.Equal(t, "golang tutorial", out.Queries.Top[0].Query)
+		require.Len(t, out.Queries.Rising, 1)
+		assert.Equal(t, "Breakout", out.Queries.Rising[0].FormattedValue)
+	})
+
+	t.Run("keywords of the rising list are rising", func(t *testing.T) {
+		related, err := Related(context.Background(), newWidget("RELATED_QUERIES_0", "golang"), langEN)
+
+		require.NoError(t, err)
+		require.Len(t, related, 3)
+		assert.False(t, related[0].isRising())
+		assert.True(t, related[2].isRising())
+		assert.Equal(t, []*RankedKeyword{related[2]}, FilterByMinValue(related, 101))
+	})
+
+	t.Run("missing widget leaves set empty", func(t *testing.T) {
+		out, err := RelatedFull(context.Background(), ExploreResponse{newWidget("RELATED_QUERIES", "golang")}, 0, langEN)
+
+		require.NoError(t, err)
+		assert.Empty(t, out.Topics.Top)
+		assert.Empty(t, out.Topics.Rising)
+		assert.Len(t, out.Queries.Top, 2)
+		assert.Len(t, out.Queries.Rising, 1)
+	})
+
+	t.Run("keeps successful widget on error", func(t *testing.T) {
+		widgets := ExploreResponse{
+			newWidget("RELATED_TOPICS_0", "broken"),
+			newWidget("RELATED_QUERIES_0", "golang"),
+		}
+
+		out, err := RelatedFull(context.Background(), widgets, 0, langEN)
+
+		assert.ErrorIs(t, err, ErrRequestFailed)
+		assert.Contains(t, err.Error(), "RELATED_TOPICS_0")
+		assert.Empty(t, out.Topics.Top)
+		assert.Len(t, out.Queries.Top, 2)
+	})
+}
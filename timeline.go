@@ -0,0 +1,824 @@
+package googletrends
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layouts used for the formatted time fields of aggregated timelines.
+const (
+	// formattedDayLayout formats the start of a daily or weekly bucket.
+	formattedDayLayout = "Jan 2, 2006"
+
+	// formattedAxisDayLayout formats the axis label of a daily or weekly bucket.
+	formattedAxisDayLayout = "Jan 2"
+
+	// formattedMonthLayout formats both the time and axis label of a monthly bucket.
+	formattedMonthLayout = "Jan 2006"
+)
+
+// Layouts of the two dates in a custom time range, e.g. "2020-01-01 2020-12-31"
+// or "2020-01-01T00 2020-01-02T12" for hourly ranges.
+const (
+	rangeDayLayout  = "2006-01-02"
+	rangeHourLayout = "2006-01-02T15"
+)
+
+// RangeResolution returns the resolution Google Trends uses for a custom time range
+// in the "YYYY-MM-DD YYYY-MM-DD" (or "YYYY-MM-DDTHH YYYY-MM-DDTHH") format:
+//   - "HOUR" for ranges shorter than 7 days
+//   - "DAY" for ranges shorter than 9 months
+//   - "WEEK" for ranges shorter than 5 years
+//   - "MONTH" for longer ranges
+//
+// The boolean result is false when timeRange is not a valid custom range
+// (e.g. relative ranges like "today 12-m" or an end before the start).
+//
+// InterestOverTime sets this resolution on the widget request for custom ranges,
+// so after the call w.Request.Resolution tells how the returned points are spaced.
+//
+// Example:
+//
+//	res, _ := googletrends.RangeResolution("2015-01-01 2023-12-31") // "MONTH"
+func RangeResolution(timeRange string) (string, bool) {
+	parts := strings.Fields(timeRange)
+	if len(parts) != 2 {
+		return "", false
+	}
+
+	layout := rangeDayLayout
+	if strings.Contains(parts[0], "T") {
+		layout = rangeHourLayout
+	}
+
+	start, err := time.Parse(layout, parts[0])
+	if err != nil {
+		return "", false
+	}
+	end, err := time.Parse(layout, parts[1])
+	if err != nil || end.Before(start) {
+		return "", false
+	}
+
+	switch {
+	case end.Before(start.AddDate(0, 0, 7)):
+		return resolutionHour, true
+	case end.Before(start.AddDate(0, 9, 0)):
+		return resolutionDay, true
+	case end.Before(start.AddDate(5, 0, 0)):
+		return resolutionWeek, true
+	}
+
+	return resolutionMonth, true
+}
+
+// timelineJSON has the fields of Timeline without its UnmarshalJSON method.
+type timelineJSON Timeline
+
+// UnmarshalJSON decodes a timeline point, also accepting the snake_case field names
+// (formatted_time, formatted_axis_time, has_data, formatted_value) some regions and
+// API versions return instead of camelCase. Other casings of the camelCase names are
+// matched by encoding/json already. When both forms are present, camelCase wins.
+func (t *Timeline) UnmarshalJSON(b []byte) error {
+	aux := struct {
+		*timelineJSON
+		FormattedTime     string   `json:"formatted_time"`
+		FormattedAxisTime string   `json:"formatted_axis_time"`
+		HasData           []bool   `json:"has_data"`
+		FormattedValue    []string `json:"formatted_value"`
+	}{timelineJSON: (*timelineJSON)(t)}
+
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+
+	if len(t.FormattedTime) == 0 {
+		t.FormattedTime = aux.FormattedTime
+	}
+	if len(t.FormattedAxisTime) == 0 {
+		t.FormattedAxisTime = aux.FormattedAxisTime
+	}
+	if t.HasData == nil {
+		t.HasData = aux.HasData
+	}
+	if t.FormattedValue == nil {
+		t.FormattedValue = aux.FormattedValue
+	}
+
+	return nil
+}
+
+// unixTime parses the Time field of a timeline point as a Unix timestamp in seconds.
+func (t *Timeline) unixTime() (int64, error) {
+	ts, err := strconv.ParseInt(t.Time, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid time %q: %w", ErrInvalidTimeline, t.Time, err)
+	}
+
+	return ts, nil
+}
+
+// TimelineByTime indexes timeline points by their Unix timestamp (in seconds).
+// Each key maps to the Value slice of the point, so two separately fetched
+// timelines can be aligned on a common time axis.
+//
+// The returned slices are shared with the input points and are not copied.
+//
+// Returns ErrInvalidTimeline if a point has an unparseable Time value or if
+// two points share the same timestamp.
+//
+// Example:
+//
+//	golang, _ := googletrends.TimelineByTime(golangTimeline)
+//	python, _ := googletrends.TimelineByTime(pythonTimeline)
+//	for ts, v := range golang {
+//	    if p, ok := python[ts]; ok {
+//	        fmt.Println(time.Unix(ts, 0), v[0], p[0])
+//	    }
+//	}
+func TimelineByTime(data []*Timeline) (map[int64][]int, error) {
+	out := make(map[int64][]int, len(data))
+	for i, v := range data {
+		if v == nil {
+			continue
+		}
+
+		ts, err := v.unixTime()
+		if err != nil {
+			return nil, fmt.Errorf("point %d: %w", i, err)
+		}
+
+		if _, ok := out[ts]; ok {
+			return nil, fmt.Errorf("%w: point %d has duplicate time %d", ErrInvalidTimeline, i, ts)
+		}
+
+		out[ts] = v.Value
+	}
+
+	return out, nil
+}
+
+// ToFrame converts the timeline data into a column-oriented frame, the shape
+// DataFrame libraries are built from: times is the parsed time axis (in UTC) and
+// values maps every keyword index to its value column, aligned with times.
+// Nil points are skipped.
+//
+// Returns ErrInvalidTimeline if a point has an unparseable Time value or if the
+// points don't all have the same number of values.
+//
+// Example:
+//
+//	timeline, _ := googletrends.InterestOverTime(ctx, widget, "EN")
+//	times, values, err := googletrends.Timelines(timeline).ToFrame()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for i, t := range times {
+//	    fmt.Println(t.Format(time.DateOnly), values[0][i])
+//	}
+func (data Timelines) ToFrame() (times []time.Time, values map[int][]int, err error) {
+	times = make([]time.Time, 0, len(data))
+	values = make(map[int][]int)
+
+	numKeywords := -1
+	for i, v := range data {
+		if v == nil {
+			continue
+		}
+
+		ts, err := v.unixTime()
+		if err != nil {
+			return nil, nil, fmt.Errorf("point %d: %w", i, err)
+		}
+
+		if numKeywords < 0 {
+			numKeywords = len(v.Value)
+		}
+		if len(v.Value) != numKeywords {
+			return nil, nil, fmt.Errorf("%w: point %d has %d values, want %d", ErrInvalidTimeline, i, len(v.Value), numKeywords)
+		}
+
+		times = append(times, time.Unix(ts, 0).UTC())
+		for k, val := range v.Value {
+			values[k] = append(values[k], val)
+		}
+	}
+
+	return times, values, nil
+}
+
+// periodStart truncates t (in UTC) to the start of its day, week or month.
+// Weeks start on Sunday, matching the weekly buckets of Google Trends.
+func periodStart(t time.Time, period string) (time.Time, error) {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch period {
+	case resolutionDay:
+		return day, nil
+	case resolutionWeek:
+		return day.AddDate(0, 0, -int(day.Weekday())), nil
+	case resolutionMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+	}
+
+	return time.Time{}, fmt.Errorf("%s: unknown period %q", errInvalidRequest, period)
+}
+
+// AggregateTimeline buckets timeline points into weekly or monthly periods.
+// Within each bucket the value of every keyword is the rounded average of the
+// points that have data for it (HasData true). A keyword without data in any
+// point of a bucket gets the value 0 and HasData false.
+//
+// Each resulting point carries the bucket start (UTC) as Time, with weeks starting
+// on Sunday and months on the 1st, and matching FormattedTime and FormattedAxisTime.
+// Points are returned in chronological order.
+//
+// Parameters:
+//   - data: Timeline points, typically with daily resolution
+//   - period: "WEEK" or "MONTH"
+//
+// Returns an error for an unknown period and ErrInvalidTimeline if a point has
+// an unparseable Time value.
+//
+// Example:
+//
+//	daily, _ := googletrends.InterestOverTime(ctx, widget, "EN")
+//	weekly, err := googletrends.AggregateTimeline(daily, "WEEK")
+func AggregateTimeline(data []*Timeline, period string) ([]*Timeline, error) {
+	if period != resolutionWeek && period != resolutionMonth {
+		return nil, fmt.Errorf("%s: unknown period %q", errInvalidRequest, period)
+	}
+
+	type bucket struct {
+		start time.Time
+		sums  []int
+		count []int
+	}
+
+	buckets := make([]*bucket, 0)
+	index := make(map[int64]*bucket)
+	for i, v := range data {
+		if v == nil {
+			continue
+		}
+
+		ts, err := v.unixTime()
+		if err != nil {
+			return nil, fmt.Errorf("point %d: %w", i, err)
+		}
+
+		start, _ := periodStart(time.Unix(ts, 0), period)
+		b, ok := index[start.Unix()]
+		if !ok {
+			b = &bucket{start: start}
+			index[start.Unix()] = b
+			buckets = append(buckets, b)
+		}
+
+		for len(b.sums) < len(v.Value) {
+			b.sums = append(b.sums, 0)
+			b.count = append(b.count, 0)
+		}
+
+		for k, val := range v.Value {
+			if k < len(v.HasData) && v.HasData[k] {
+				b.sums[k] += val
+				b.count[k]++
+			}
+		}
+	}
+
+	out := make([]*Timeline, 0, len(buckets))
+	for _, b := range buckets {
+		point := &Timeline{
+			Value:          make([]int, len(b.sums)),
+			HasData:        make([]bool, len(b.sums)),
+			FormattedValue: make([]string, len(b.sums)),
+		}
+		setPeriodTime(point, b.start, period)
+
+		for k := range b.sums {
+			if b.count[k] > 0 {
+				point.Value[k] = int(math.Round(float64(b.sums[k]) / float64(b.count[k])))
+				point.HasData[k] = true
+			}
+			point.FormattedValue[k] = strconv.Itoa(point.Value[k])
+		}
+
+		out = append(out, point)
+	}
+
+	sortTimeline(out)
+
+	return out, nil
+}
+
+// setPeriodTime sets the Time, FormattedTime and FormattedAxisTime of a point
+// to start, formatted for a day, week or month period.
+func setPeriodTime(point *Timeline, start time.Time, period string) {
+	point.Time = strconv.FormatInt(start.Unix(), 10)
+
+	if period == resolutionMonth {
+		point.FormattedTime = start.Format(formattedMonthLayout)
+		point.FormattedAxisTime = start.Format(formattedMonthLayout)
+		return
+	}
+
+	point.FormattedTime = start.Format(formattedDayLayout)
+	point.FormattedAxisTime = start.Format(formattedAxisDayLayout)
+}
+
+// AlignToCalendar snaps the time of every timeline point to the start (UTC) of its
+// day, week or month, which eases joining the data with other calendar-based series.
+// Weeks start on Sunday, as in AggregateTimeline. FormattedTime and FormattedAxisTime
+// are set to match the new time.
+//
+// Unlike AggregateTimeline, points are neither merged nor reordered: the result has
+// one point per non-nil input point, so points of the same period share a time.
+// The returned points are copies, but their Value, HasData and FormattedValue slices
+// are shared with the input points.
+//
+// Parameters:
+//   - data: Timeline points
+//   - unit: "DAY", "WEEK" or "MONTH"
+//
+// Returns an error for an unknown unit and ErrInvalidTimeline if a point has
+// an unparseable Time value.
+//
+// Example:
+//
+//	timeline, _ := googletrends.InterestOverTime(ctx, widget, "EN")
+//	monthly, err := googletrends.AlignToCalendar(timeline, "MONTH")
+func AlignToCalendar(data []*Timeline, unit string) ([]*Timeline, error) {
+	if unit != resolutionDay && unit != resolutionWeek && unit != resolutionMonth {
+		return nil, fmt.Errorf("%s: unknown unit %q", errInvalidRequest, unit)
+	}
+
+	out := make([]*Timeline, 0, len(data))
+	for i, v := range data {
+		if v == nil {
+			continue
+		}
+
+		ts, err := v.unixTime()
+		if err != nil {
+			return nil, fmt.Errorf("point %d: %w", i, err)
+		}
+
+		start, _ := periodStart(time.Unix(ts, 0), unit)
+
+		point := *v
+		setPeriodTime(&point, start, unit)
+		out = append(out, &point)
+	}
+
+	return out, nil
+}
+
+// sortTimeline sorts timeline points with numeric Time values in chronological order.
+func sortTimeline(data []*Timeline) {
+	sort.SliceStable(data, func(i, j int) bool {
+		ti, _ := data[i].unixTime()
+		tj, _ := data[j].unixTime()
+		return ti < tj
+	})
+}
+
+// seasonalityThreshold is the minimum autocorrelation DetectSeasonality
+// reports as a seasonal pattern.
+const seasonalityThreshold = 0.3
+
+// harmonicTolerance is the fraction of the strongest autocorrelation peak a shorter
+// lag must reach to be preferred over it, so multiples of a period are not reported.
+const harmonicTolerance = 0.9
+
+// series extracts the values of the keyword at keywordIndex together with
+// a mask telling which points have data for it.
+func series(data []*Timeline, keywordIndex int) ([]float64, []bool) {
+	values := make([]float64, len(data))
+	valid := make([]bool, len(data))
+	for i, v := range data {
+		if v == nil || keywordIndex < 0 || keywordIndex >= len(v.Value) || keywordIndex >= len(v.HasData) {
+			continue
+		}
+
+		values[i] = float64(v.Value[keywordIndex])
+		valid[i] = v.HasData[keywordIndex]
+	}
+
+	return values, valid
+}
+
+// DetectSeasonality looks for a repeating pattern in the values of the keyword at
+// keywordIndex using the autocorrelation of the series.
+//
+// The autocorrelation is computed for every lag from 2 up to half the series length,
+// using only pairs of points that both have data (HasData true), so gaps don't
+// count as zero interest. The period is the lag of the strongest local autocorrelation
+// peak, and strength is its autocorrelation coefficient (up to 1.0 for a perfectly
+// repeating pattern). Since multiples of a period repeat as well, the shortest lag
+// whose peak is within 10% of the strongest one is reported. The period is expressed
+// in timeline points, so 52 on weekly data means a yearly cycle.
+//
+// Returns period 0 and strength 0 when there is no clear seasonality: the series is
+// too short or flat, or no peak reaches an autocorrelation of 0.3.
+//
+// Example:
+//
+//	timeline, _ := googletrends.InterestOverTime(ctx, widget, "EN")
+//	if period, strength := googletrends.DetectSeasonality(timeline, 0); period > 0 {
+//	    fmt.Printf("repeats every %d points (strength %.2f)\n", period, strength)
+//	}
+func DetectSeasonality(data []*Timeline, keywordIndex int) (period int, strength float64) {
+	values, valid := series(data, keywordIndex)
+
+	var sum float64
+	var n int
+	for i, v := range values {
+		if valid[i] {
+			sum += v
+			n++
+		}
+	}
+	if n < 4 {
+		return 0, 0
+	}
+	mean := sum / float64(n)
+
+	var variance float64
+	for i, v := range values {
+		if valid[i] {
+			variance += (v - mean) * (v - mean)
+		}
+	}
+	variance /= float64(n)
+	if variance == 0 {
+		return 0, 0
+	}
+
+	maxLag := len(values) / 2
+	acf := make([]float64, maxLag+2)
+	for lag := 1; lag <= maxLag+1 && lag < len(values); lag++ {
+		var cov float64
+		var pairs int
+		for i := 0; i+lag < len(values); i++ {
+			if valid[i] && valid[i+lag] {
+				cov += (values[i] - mean) * (values[i+lag] - mean)
+				pairs++
+			}
+		}
+		if pairs > 0 {
+			acf[lag] = cov / float64(pairs) / variance
+		}
+	}
+
+	peaks := make([]int, 0)
+	var best float64
+	for lag := 2; lag <= maxLag; lag++ {
+		if acf[lag] > acf[lag-1] && acf[lag] >= acf[lag+1] {
+			peaks = append(peaks, lag)
+			best = math.Max(best, acf[lag])
+		}
+	}
+
+	if best < seasonalityThreshold {
+		return 0, 0
+	}
+
+	// multiples of the period peak as high as the period itself, prefer the shortest
+	for _, lag := range peaks {
+		if acf[lag] >= best*harmonicTolerance {
+			return lag, math.Min(acf[lag], 1)
+		}
+	}
+
+	return 0, 0
+}
+
+// ShareOfVoice computes, for every timeline point, the fraction of the total interest
+// held by each of the numKeywords compared keywords. It answers "what share of
+// attention does each brand hold over time" for competitive analysis.
+//
+// The result has one row per point of data and numKeywords columns. Values of a row
+// sum to 1.0, except for points with zero total interest, whose row is all zeros.
+// Values without data (HasData false, or slices shorter than numKeywords) count as
+// zero interest, so they neither get a share nor change the shares of the others.
+//
+// Example:
+//
+//	timeline, _ := googletrends.InterestOverTime(ctx, widget, "EN")
+//	for i, shares := range googletrends.ShareOfVoice(timeline, 2) {
+//	    fmt.Printf("%s: %.0f%% vs %.0f%%\n", timeline[i].FormattedTime, shares[0]*100, shares[1]*100)
+//	}
+func ShareOfVoice(data []*Timeline, numKeywords int) [][]float64 {
+	if numKeywords < 0 {
+		numKeywords = 0
+	}
+
+	out := make([][]float64, len(data))
+	for i, v := range data {
+		shares := make([]float64, numKeywords)
+		out[i] = shares
+		if v == nil {
+			continue
+		}
+
+		var total float64
+		for k := range shares {
+			if k < len(v.Value) && k < len(v.HasData) && v.HasData[k] {
+				shares[k] = float64(v.Value[k])
+				total += shares[k]
+			}
+		}
+
+		if total == 0 {
+			continue
+		}
+		for k := range shares {
+			shares[k] /= total
+		}
+	}
+
+	return out
+}
+
+// NormalizeTimeline min-max scales the values of every keyword to the range 0.0-1.0,
+// independently per keyword, as feature pipelines expect. The result has one row per
+// point of data, aligned with data, and one column per keyword (the longest Value slice).
+//
+// The minimum and maximum of a keyword are taken over the points that have data for it.
+// Points without data (HasData false, slices too short, or nil points) are NaN, so use
+// math.IsNaN to mask them. A keyword with a single distinct value scales to 0.
+//
+// Example:
+//
+//	timeline, _ := googletrends.InterestOverTime(ctx, widget, "EN")
+//	for i, row := range googletrends.NormalizeTimeline(timeline) {
+//	    fmt.Println(timeline[i].FormattedTime, row)
+//	}
+func NormalizeTimeline(data []*Timeline) [][]float64 {
+	var numKeywords int
+	for _, v := range data {
+		if v != nil && len(v.Value) > numKeywords {
+			numKeywords = len(v.Value)
+		}
+	}
+
+	out := make([][]float64, len(data))
+	for i := range out {
+		out[i] = make([]float64, numKeywords)
+	}
+
+	for k := 0; k < numKeywords; k++ {
+		values, valid := series(data, k)
+
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for i, v := range values {
+			if valid[i] {
+				lo = math.Min(lo, v)
+				hi = math.Max(hi, v)
+			}
+		}
+
+		for i, v := range values {
+			switch {
+			case !valid[i]:
+				out[i][k] = math.NaN()
+			case hi > lo:
+				out[i][k] = (v - lo) / (hi - lo)
+			}
+		}
+	}
+
+	return out
+}
+
+// FillTimelineGaps returns a gap-free copy of data for charting libraries that don't
+// handle missing points. Every value without data (HasData false) is replaced by the
+// last value with data of the same keyword, or 0 before the first one, and marked in
+// the Filled slice of the point. FormattedValue is updated to match.
+//
+// HasData is left false for filled values, so the analysis helpers of this package
+// still skip them. Nil points are dropped. The input points are not modified.
+//
+// Example:
+//
+//	timeline, _ := googletrends.InterestOverTime(ctx, widget, "EN")
+//	for _, point := range googletrends.FillTimelineGaps(timeline) {
+//	    fmt.Println(point.FormattedTime, point.Value, point.Filled)
+//	}
+func FillTimelineGaps(data []*Timeline) []*Timeline {
+	out := make([]*Timeline, 0, len(data))
+	last := make([]int, 0)
+	for _, v := range data {
+		if v == nil {
+			continue
+		}
+
+		point := *v
+		point.Value = append([]int(nil), v.Value...)
+		point.FormattedValue = append([]string(nil), v.FormattedValue...)
+		point.Filled = make([]bool, len(v.Value))
+		for len(last) < len(v.Value) {
+			last = append(last, 0)
+		}
+
+		for k, val := range v.Value {
+			if k < len(v.HasData) && v.HasData[k] {
+				last[k] = val
+				continue
+			}
+
+			point.Value[k] = last[k]
+			point.Filled[k] = true
+			if k < len(point.FormattedValue) {
+				point.FormattedValue[k] = strconv.Itoa(last[k])
+			}
+		}
+
+		out = append(out, &point)
+	}
+
+	return out
+}
+
+// MovingAverage returns the trailing moving average of the values of the keyword at
+// keywordIndex over window points, aligned with data, to smooth noisy series before
+// charting.
+//
+// Every average is taken over the points of its window that have data (HasData true),
+// so gaps don't drag it towards zero, and the first points average over the fewer
+// points before them. A point whose whole window has no data is NaN, so use math.IsNaN
+// to mask it. Returns nil if window is not positive.
+//
+// Example:
+//
+//	timeline, _ := googletrends.InterestOverTime(ctx, widget, "EN")
+//	smooth := googletrends.MovingAverage(timeline, 0, 4)
+func MovingAverage(data []*Timeline, keywordIndex, window int) []float64 {
+	if window <= 0 {
+		return nil
+	}
+
+	values, valid := series(data, keywordIndex)
+
+	out := make([]float64, len(values))
+	var sum float64
+	var n int
+	for i, v := range values {
+		if valid[i] {
+			sum += v
+			n++
+		}
+
+		// drop the point leaving the window
+		if j := i - window; j >= 0 && valid[j] {
+			sum -= values[j]
+			n--
+		}
+
+		if n == 0 {
+			out[i] = math.NaN()
+			continue
+		}
+		out[i] = sum / float64(n)
+	}
+
+	return out
+}
+
+// sparkBlocks are the block characters of a sparkline, from lowest to highest.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders the values of the keyword at keywordIndex as a Unicode sparkline
+// (▁▂▃▄▅▆▇█), one character per timeline point, for terminal dashboards.
+//
+// Values are scaled between the minimum and maximum of the points that have data,
+// so the full height is used even for low-interest keywords. Points without data
+// are rendered as spaces, and a series with a single distinct value renders as ▁.
+//
+// Example:
+//
+//	timeline, _ := googletrends.InterestOverTime(ctx, widget, "EN")
+//	fmt.Println(googletrends.Sparkline(timeline, 0)) // ▁▂▄▇█▆▃ ▂
+func Sparkline(data []*Timeline, keywordIndex int) string {
+	values, valid := series(data, keywordIndex)
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for i, v := range values {
+		if valid[i] {
+			lo = math.Min(lo, v)
+			hi = math.Max(hi, v)
+		}
+	}
+
+	var b strings.Builder
+	for i, v := range values {
+		if !valid[i] {
+			b.WriteRune(' ')
+			continue
+		}
+
+		idx := 0
+		if hi > lo {
+			idx = int(math.Round((v - lo) / (hi - lo) * float64(len(sparkBlocks)-1)))
+		}
+		b.WriteRune(sparkBlocks[idx])
+	}
+
+	return b.String()
+}
+
+// HasAnyData reports whether any point of data has data for any keyword
+// (a HasData flag set). Comparisons of obscure keywords often come back with every
+// point flagged as missing; checking this first avoids rendering empty charts.
+//
+// Example:
+//
+//	timeline, _ := googletrends.InterestOverTime(ctx, widget, "EN")
+//	if !googletrends.Timelines(timeline).HasAnyData() {
+//	    return // nothing to chart
+//	}
+func (data Timelines) HasAnyData() bool {
+	for _, v := range data {
+		if v == nil {
+			continue
+		}
+
+		for k := range v.Value {
+			if k < len(v.HasData) && v.HasData[k] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Weights of the two components of InterestIndex. Timeline interest dominates, as it
+// measures how much the keyword is searched; geographic coverage only tells how widely.
+const (
+	interestIndexTimelineWeight = 0.7
+	interestIndexGeoWeight      = 0.3
+)
+
+// InterestIndex combines the timeline and geographic interest of the keyword at
+// keywordIndex into a single 0-100 score, to rank keywords by overall popularity.
+//
+// The score is
+//
+//	0.7 * average + 0.3 * 100 * coverage
+//
+// where average is the mean timeline value (0-100) over the points that have data
+// (HasData true) and coverage is the fraction of regions of geo that have data for the
+// keyword. Average interest weighs more as it reflects search volume, while coverage
+// rewards keywords searched across many regions over ones popular in a single place.
+// Both timeline and geo should come from the same comparison, so values are on the
+// same scale across keywords.
+//
+// Returns 0 if timeline or geo is empty.
+//
+// Example:
+//
+//	timeline, _ := googletrends.InterestOverTime(ctx, timelineWidget, "EN")
+//	regions, _ := googletrends.InterestByLocation(ctx, geoWidget, "EN")
+//	for i, kw := range keywords {
+//	    fmt.Printf("%s: %.1f\n", kw, googletrends.InterestIndex(timeline, regions, i))
+//	}
+func InterestIndex(timeline []*Timeline, geo []*GeoMap, keywordIndex int) float64 {
+	if len(timeline) == 0 || len(geo) == 0 {
+		return 0
+	}
+
+	values, valid := series(timeline, keywordIndex)
+
+	var sum float64
+	var n int
+	for i, v := range values {
+		if valid[i] {
+			sum += v
+			n++
+		}
+	}
+
+	var average float64
+	if n > 0 {
+		average = sum / float64(n)
+	}
+
+	var covered int
+	for _, v := range geo {
+		if v.hasValue(keywordIndex) {
+			covered++
+		}
+	}
+	coverage := float64(covered) / float64(len(geo))
+
+	return interestIndexTimelineWeight*average + interestIndexGeoWeight*100*coverage
+}
@@ -0,0 +1,674 @@
+package googletrends
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimelineByTime(t *testing.T) {
+	t.Parallel()
+
+	t.Run("indexes values by timestamp", func(t *testing.T) {
+		data := []*Timeline{
+			{Time: "1609459200", Value: []int{10, 20}},
+			{Time: "1610064000", Value: []int{30, 40}},
+			nil,
+		}
+
+		out, err := TimelineByTime(data)
+
+		require.NoError(t, err)
+		assert.Len(t, out, 2)
+		assert.Equal(t, []int{10, 20}, out[1609459200])
+		assert.Equal(t, []int{30, 40}, out[1610064000])
+	})
+
+	t.Run("empty input returns empty map", func(t *testing.T) {
+		out, err := TimelineByTime(nil)
+
+		require.NoError(t, err)
+		assert.Empty(t, out)
+	})
+
+	tests := []struct {
+		name string
+		data []*Timeline
+	}{
+		{
+			name: "unparseable timestamp",
+			data: []*Timeline{{Time: "Jan 1, 2021", Value: []int{1}}},
+		},
+		{
+			name: "duplicate timestamp",
+			data: []*Timeline{
+				{Time: "1609459200", Value: []int{1}},
+				{Time: "1609459200", Value: []int{2}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := TimelineByTime(tt.data)
+
+			assert.Nil(t, out)
+			assert.True(t, errors.Is(err, ErrInvalidTimeline))
+		})
+	}
+}
+
+func TestTimelinesToFrame(t *testing.T) {
+	t.Parallel()
+
+	t.Run("builds columns aligned with time axis", func(t *testing.T) {
+		data := []*Timeline{
+			{Time: "1609459200", Value: []int{10, 20}},
+			nil,
+			{Time: "1610064000", Value: []int{30, 0}, HasData: []bool{true, false}},
+		}
+
+		times, values, err := Timelines(data).ToFrame()
+
+		require.NoError(t, err)
+		assert.Equal(t, []time.Time{
+			time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2021, time.January, 8, 0, 0, 0, 0, time.UTC),
+		}, times)
+		assert.Equal(t, map[int][]int{0: {10, 30}, 1: {20, 0}}, values)
+	})
+
+	t.Run("empty input returns empty frame", func(t *testing.T) {
+		times, values, err := Timelines(nil).ToFrame()
+
+		require.NoError(t, err)
+		assert.Empty(t, times)
+		assert.Empty(t, values)
+	})
+
+	tests := []struct {
+		name string
+		data []*Timeline
+	}{
+		{
+			name: "unparseable timestamp",
+			data: []*Timeline{{Time: "Jan 1, 2021", Value: []int{1}}},
+		},
+		{
+			name: "ragged values",
+			data: []*Timeline{
+				{Time: "1609459200", Value: []int{1, 2}},
+				{Time: "1610064000", Value: []int{3}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			times, values, err := Timelines(tt.data).ToFrame()
+
+			assert.Nil(t, times)
+			assert.Nil(t, values)
+			assert.True(t, errors.Is(err, ErrInvalidTimeline))
+		})
+	}
+}
+
+// unixDay returns the Unix timestamp of a UTC date as a Timeline Time value.
+func unixDay(year int, month time.Month, day int) string {
+	return strconv.FormatInt(time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix(), 10)
+}
+
+func TestAggregateTimeline(t *testing.T) {
+	t.Parallel()
+
+	// Jan 3, 2021 is a Sunday
+	data := []*Timeline{
+		{Time: unixDay(2021, time.January, 10), Value: []int{30, 5}, HasData: []bool{true, false}},
+		{Time: unixDay(2021, time.January, 3), Value: []int{10, 0}, HasData: []bool{true, false}},
+		{Time: unixDay(2021, time.January, 4), Value: []int{21, 0}, HasData: []bool{true, false}},
+		{Time: unixDay(2021, time.January, 9), Value: []int{0, 0}, HasData: []bool{false, false}},
+		{Time: unixDay(2021, time.February, 1), Value: []int{50, 40}, HasData: []bool{true, true}},
+	}
+
+	t.Run("weekly buckets", func(t *testing.T) {
+		out, err := AggregateTimeline(data, "WEEK")
+		require.NoError(t, err)
+		require.Len(t, out, 3)
+
+		assert.Equal(t, unixDay(2021, time.January, 3), out[0].Time)
+		assert.Equal(t, "Jan 3, 2021", out[0].FormattedTime)
+		assert.Equal(t, "Jan 3", out[0].FormattedAxisTime)
+		assert.Equal(t, []int{16, 0}, out[0].Value)
+		assert.Equal(t, []bool{true, false}, out[0].HasData)
+		assert.Equal(t, []string{"16", "0"}, out[0].FormattedValue)
+
+		assert.Equal(t, unixDay(2021, time.January, 10), out[1].Time)
+		assert.Equal(t, []int{30, 0}, out[1].Value)
+
+		// Feb 1, 2021 is a Monday
+		assert.Equal(t, unixDay(2021, time.January, 31), out[2].Time)
+		assert.Equal(t, []bool{true, true}, out[2].HasData)
+	})
+
+	t.Run("monthly buckets", func(t *testing.T) {
+		out, err := AggregateTimeline(data, "MONTH")
+		require.NoError(t, err)
+		require.Len(t, out, 2)
+
+		assert.Equal(t, unixDay(2021, time.January, 1), out[0].Time)
+		assert.Equal(t, "Jan 2021", out[0].FormattedTime)
+		assert.Equal(t, []int{20, 0}, out[0].Value)
+		assert.Equal(t, []bool{true, false}, out[0].HasData)
+
+		assert.Equal(t, unixDay(2021, time.February, 1), out[1].Time)
+		assert.Equal(t, []int{50, 40}, out[1].Value)
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		_, err := AggregateTimeline(data, "DAY")
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid timestamp", func(t *testing.T) {
+		_, err := AggregateTimeline([]*Timeline{{Time: "yesterday"}}, "WEEK")
+		assert.ErrorIs(t, err, ErrInvalidTimeline)
+	})
+}
+
+func TestAlignToCalendar(t *testing.T) {
+	t.Parallel()
+
+	// Jan 6, 2021 is a Wednesday
+	noon := time.Date(2021, time.January, 6, 12, 30, 0, 0, time.UTC).Unix()
+	data := []*Timeline{
+		{Time: strconv.FormatInt(noon, 10), FormattedTime: "Jan 6, 2021 at 12:30 PM", Value: []int{30}, HasData: []bool{true}},
+		nil,
+		{Time: unixDay(2021, time.February, 1), Value: []int{50}, HasData: []bool{true}},
+	}
+
+	tests := []struct {
+		name              string
+		unit              string
+		expectedTimes     []string
+		expectedFormatted []string
+	}{
+		{
+			name:              "day",
+			unit:              "DAY",
+			expectedTimes:     []string{unixDay(2021, time.January, 6), unixDay(2021, time.February, 1)},
+			expectedFormatted: []string{"Jan 6, 2021", "Feb 1, 2021"},
+		},
+		{
+			name:              "week",
+			unit:              "WEEK",
+			expectedTimes:     []string{unixDay(2021, time.January, 3), unixDay(2021, time.January, 31)},
+			expectedFormatted: []string{"Jan 3, 2021", "Jan 31, 2021"},
+		},
+		{
+			name:              "month",
+			unit:              "MONTH",
+			expectedTimes:     []string{unixDay(2021, time.January, 1), unixDay(2021, time.February, 1)},
+			expectedFormatted: []string{"Jan 2021", "Feb 2021"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := AlignToCalendar(data, tt.unit)
+			require.NoError(t, err)
+			require.Len(t, out, 2)
+
+			for i, p := range out {
+				assert.Equal(t, tt.expectedTimes[i], p.Time)
+				assert.Equal(t, tt.expectedFormatted[i], p.FormattedTime)
+			}
+			assert.Equal(t, []int{30}, out[0].Value)
+
+			// the input points are left untouched
+			assert.Equal(t, strconv.FormatInt(noon, 10), data[0].Time)
+		})
+	}
+
+	t.Run("invalid unit", func(t *testing.T) {
+		_, err := AlignToCalendar(data, "YEAR")
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid timestamp", func(t *testing.T) {
+		_, err := AlignToCalendar([]*Timeline{{Time: "yesterday"}}, "DAY")
+		assert.ErrorIs(t, err, ErrInvalidTimeline)
+	})
+}
+
+func TestDetectSeasonality(t *testing.T) {
+	t.Parallel()
+
+	// seasonal builds n points repeating every period points, with gaps at the given indexes.
+	seasonal := func(n, period int, gaps ...int) []*Timeline {
+		data := make([]*Timeline, n)
+		for i := range data {
+			v := 50 + int(40*math.Sin(2*math.Pi*float64(i)/float64(period)))
+			data[i] = &Timeline{Time: strconv.Itoa(i), Value: []int{v}, HasData: []bool{true}}
+		}
+		for _, g := range gaps {
+			data[g].Value[0] = 0
+			data[g].HasData[0] = false
+		}
+		return data
+	}
+
+	linear := make([]*Timeline, 60)
+	for i := range linear {
+		linear[i] = &Timeline{Value: []int{i}, HasData: []bool{true}}
+	}
+
+	flat := make([]*Timeline, 60)
+	for i := range flat {
+		flat[i] = &Timeline{Value: []int{42}, HasData: []bool{true}}
+	}
+
+	tests := []struct {
+		name           string
+		data           []*Timeline
+		index          int
+		expectedPeriod int
+	}{
+		{
+			name:           "yearly cycle on monthly data",
+			data:           seasonal(60, 12),
+			expectedPeriod: 12,
+		},
+		{
+			name:           "gaps are skipped",
+			data:           seasonal(60, 12, 3, 4, 17, 30, 31, 32, 45),
+			expectedPeriod: 12,
+		},
+		{
+			name:           "trend without cycle",
+			data:           linear,
+			expectedPeriod: 0,
+		},
+		{
+			name:           "flat series",
+			data:           flat,
+			expectedPeriod: 0,
+		},
+		{
+			name:           "index out of range",
+			data:           seasonal(60, 12),
+			index:          1,
+			expectedPeriod: 0,
+		},
+		{
+			name:           "too short series",
+			data:           seasonal(3, 2),
+			expectedPeriod: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			period, strength := DetectSeasonality(tt.data, tt.index)
+
+			assert.Equal(t, tt.expectedPeriod, period)
+			if tt.expectedPeriod > 0 {
+				assert.Greater(t, strength, 0.8)
+				assert.LessOrEqual(t, strength, 1.0)
+			} else {
+				assert.Zero(t, strength)
+			}
+		})
+	}
+}
+
+func TestRangeResolution(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		timeRange  string
+		expected   string
+		expectedOK bool
+	}{
+		{timeRange: "2021-01-01T00 2021-01-02T12", expected: "HOUR", expectedOK: true},
+		{timeRange: "2021-01-01 2021-01-07", expected: "HOUR", expectedOK: true},
+		{timeRange: "2021-01-01 2021-01-08", expected: "DAY", expectedOK: true},
+		{timeRange: "2021-01-01 2021-09-30", expected: "DAY", expectedOK: true},
+		{timeRange: "2021-01-01 2021-10-01", expected: "WEEK", expectedOK: true},
+		{timeRange: "2016-01-01 2020-12-31", expected: "WEEK", expectedOK: true},
+		{timeRange: "2016-01-01 2021-01-01", expected: "MONTH", expectedOK: true},
+		{timeRange: "2015-01-01 2023-12-31", expected: "MONTH", expectedOK: true},
+		{timeRange: "today 12-m", expectedOK: false},
+		{timeRange: "all", expectedOK: false},
+		{timeRange: "2021-02-01 2021-01-01", expectedOK: false},
+		{timeRange: "2021-13-01 2021-14-01", expectedOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.timeRange, func(t *testing.T) {
+			res, ok := RangeResolution(tt.timeRange)
+
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
+
+func TestShareOfVoice(t *testing.T) {
+	t.Parallel()
+
+	data := []*Timeline{
+		{Value: []int{30, 10}, HasData: []bool{true, true}},
+		{Value: []int{0, 0}, HasData: []bool{true, true}},
+		{Value: []int{50, 25}, HasData: []bool{true, false}},
+		{Value: []int{20}, HasData: []bool{true}},
+		nil,
+	}
+
+	tests := []struct {
+		name        string
+		data        []*Timeline
+		numKeywords int
+		expected    [][]float64
+	}{
+		{
+			name:        "shares per point",
+			data:        data,
+			numKeywords: 2,
+			expected: [][]float64{
+				{0.75, 0.25},
+				{0, 0},
+				{1, 0},
+				{1, 0},
+				{0, 0},
+			},
+		},
+		{
+			name:        "empty data",
+			data:        nil,
+			numKeywords: 2,
+			expected:    [][]float64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ShareOfVoice(tt.data, tt.numKeywords)
+
+			require.Len(t, got, len(tt.expected))
+			for i := range tt.expected {
+				assert.InDeltaSlice(t, tt.expected[i], got[i], 1e-9)
+			}
+		})
+	}
+}
+
+func TestNormalizeTimeline(t *testing.T) {
+	t.Parallel()
+
+	data := []*Timeline{
+		{Value: []int{10, 50, 7}, HasData: []bool{true, true, true}},
+		{Value: []int{30, 0, 7}, HasData: []bool{true, false, true}},
+		nil,
+		{Value: []int{20, 100}, HasData: []bool{true, true}},
+		{Value: []int{60, 75, 7}, HasData: []bool{true, true, true}},
+	}
+
+	out := NormalizeTimeline(data)
+	require.Len(t, out, 5)
+
+	nan := math.NaN()
+	expected := [][]float64{
+		{0, 0, 0},
+		{0.4, nan, 0},
+		{nan, nan, nan},
+		{0.2, 1, nan},
+		{1, 0.5, 0},
+	}
+	for i, row := range expected {
+		require.Len(t, out[i], 3)
+		for k, v := range row {
+			if math.IsNaN(v) {
+				assert.True(t, math.IsNaN(out[i][k]), "point %d keyword %d", i, k)
+				continue
+			}
+			assert.InDelta(t, v, out[i][k], 1e-9, "point %d keyword %d", i, k)
+		}
+	}
+
+	assert.Empty(t, NormalizeTimeline(nil))
+}
+
+func TestMovingAverage(t *testing.T) {
+	t.Parallel()
+
+	data := []*Timeline{
+		{Value: []int{0, 10}, HasData: []bool{false, true}},
+		{Value: []int{20, 30}, HasData: []bool{true, true}},
+		{Value: []int{40, 0}, HasData: []bool{true, false}},
+		nil,
+		{Value: []int{0, 0}, HasData: []bool{false, false}},
+		{Value: []int{90, 50}, HasData: []bool{true, true}},
+	}
+
+	nan := math.NaN()
+	tests := []struct {
+		name         string
+		keywordIndex int
+		window       int
+		expected     []float64
+	}{
+		{
+			name:         "skips gaps and averages available points",
+			keywordIndex: 0,
+			window:       3,
+			expected:     []float64{nan, 20, 30, 30, 40, 90},
+		},
+		{
+			name:         "window of one keeps values",
+			keywordIndex: 1,
+			window:       1,
+			expected:     []float64{10, 30, nan, nan, nan, 50},
+		},
+		{
+			name:         "window longer than data",
+			keywordIndex: 1,
+			window:       10,
+			expected:     []float64{10, 20, 20, 20, 20, 30},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := MovingAverage(data, tt.keywordIndex, tt.window)
+
+			require.Len(t, out, len(tt.expected))
+			for i, v := range tt.expected {
+				if math.IsNaN(v) {
+					assert.True(t, math.IsNaN(out[i]), "point %d", i)
+					continue
+				}
+				assert.InDelta(t, v, out[i], 1e-9, "point %d", i)
+			}
+		})
+	}
+
+	assert.Nil(t, MovingAverage(data, 0, 0))
+	assert.Nil(t, MovingAverage(data, 0, -2))
+	assert.Empty(t, MovingAverage(nil, 0, 3))
+}
+
+func TestFillTimelineGaps(t *testing.T) {
+	t.Parallel()
+
+	data := []*Timeline{
+		{Time: "1", Value: []int{0, 40}, HasData: []bool{false, true}, FormattedValue: []string{"0", "40"}},
+		{Time: "2", Value: []int{25, 0}, HasData: []bool{true, false}, FormattedValue: []string{"25", "0"}},
+		nil,
+		{Time: "3", Value: []int{0, 0}, HasData: []bool{false, false}, FormattedValue: []string{"0", "0"}},
+		{Time: "4", Value: []int{30, 10}, HasData: []bool{true, true}, FormattedValue: []string{"30", "10"}},
+	}
+
+	out := FillTimelineGaps(data)
+	require.Len(t, out, 4)
+
+	assert.Equal(t, []int{0, 40}, out[0].Value)
+	assert.Equal(t, []bool{true, false}, out[0].Filled)
+	assert.Equal(t, []int{25, 40}, out[1].Value)
+	assert.Equal(t, []bool{false, true}, out[1].Filled)
+	assert.Equal(t, []string{"25", "40"}, out[1].FormattedValue)
+	assert.Equal(t, []int{25, 40}, out[2].Value)
+	assert.Equal(t, []bool{true, true}, out[2].Filled)
+	assert.Equal(t, "3", out[2].Time)
+	assert.Equal(t, []int{30, 10}, out[3].Value)
+	assert.Equal(t, []bool{false, false}, out[3].Filled)
+
+	// HasData stays truthful and the input is left untouched
+	assert.Equal(t, []bool{false, false}, out[2].HasData)
+	assert.Equal(t, []int{25, 0}, data[1].Value)
+	assert.Nil(t, data[1].Filled)
+
+	assert.Empty(t, FillTimelineGaps(nil))
+}
+
+func TestSparkline(t *testing.T) {
+	t.Parallel()
+
+	points := func(values []int, hasData ...bool) []*Timeline {
+		data := make([]*Timeline, len(values))
+		for i, v := range values {
+			ok := true
+			if i < len(hasData) {
+				ok = hasData[i]
+			}
+			data[i] = &Timeline{Value: []int{v}, HasData: []bool{ok}}
+		}
+		return data
+	}
+
+	tests := []struct {
+		name     string
+		data     []*Timeline
+		index    int
+		expected string
+	}{
+		{
+			name:     "full range",
+			data:     points([]int{0, 14, 29, 43, 57, 71, 86, 100}),
+			expected: "▁▂▃▄▅▆▇█",
+		},
+		{
+			name:     "scaled to series min and max",
+			data:     points([]int{10, 15, 20}),
+			expected: "▁▅█",
+		},
+		{
+			name:     "points without data are blank",
+			data:     points([]int{0, 0, 100, 50}, true, false, true, true),
+			expected: "▁ █▅",
+		},
+		{
+			name:     "flat series",
+			data:     points([]int{42, 42, 42}),
+			expected: "▁▁▁",
+		},
+		{
+			name:     "index out of range",
+			data:     points([]int{1, 2}),
+			index:    1,
+			expected: "  ",
+		},
+		{
+			name:     "empty data",
+			data:     nil,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Sparkline(tt.data, tt.index))
+		})
+	}
+}
+
+func TestTimelinesHasAnyData(t *testing.T) {
+	t.Parallel()
+
+	empty := &Timeline{Value: []int{0, 0}, HasData: []bool{false, false}}
+
+	assert.False(t, Timelines(nil).HasAnyData())
+	assert.False(t, Timelines{empty, nil, empty}.HasAnyData())
+	// flags without a matching value are ignored
+	assert.False(t, Timelines{{Value: []int{0}, HasData: []bool{false, true}}}.HasAnyData())
+	assert.True(t, Timelines{empty, {Value: []int{0, 3}, HasData: []bool{false, true}}}.HasAnyData())
+}
+
+func TestInterestIndex(t *testing.T) {
+	t.Parallel()
+
+	timeline := []*Timeline{
+		{Value: []int{40, 100, 0}, HasData: []bool{true, true, false}},
+		{Value: []int{0, 100, 0}, HasData: []bool{false, true, false}},
+		nil,
+		{Value: []int{60, 100, 0}, HasData: []bool{true, true, false}},
+	}
+	geo := []*GeoMap{
+		{Value: []int{80, 100, 0}, HasData: []bool{true, true, false}},
+		{Value: []int{0, 70, 0}, HasData: []bool{false, true, false}},
+		{Value: []int{30, 50, 0}, HasData: []bool{true, true, false}},
+		{Value: []int{0, 20, 0}, HasData: []bool{false, true, false}},
+	}
+
+	// 0.7 * 50 average + 0.3 * 100 * 2/4 regions
+	assert.InDelta(t, 50, InterestIndex(timeline, geo, 0), 1e-9)
+	assert.InDelta(t, 100, InterestIndex(timeline, geo, 1), 1e-9)
+	assert.Zero(t, InterestIndex(timeline, geo, 2))
+	assert.Zero(t, InterestIndex(timeline, geo, 3))
+	assert.Zero(t, InterestIndex(nil, geo, 0))
+	assert.Zero(t, InterestIndex(timeline, nil, 0))
+}
+
+func TestTimelineUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	expected := &Timeline{
+		Time:              "1609459200",
+		FormattedTime:     "Jan 1, 2021",
+		FormattedAxisTime: "Jan 1",
+		Value:             []int{10, 0},
+		HasData:           []bool{true, false},
+		FormattedValue:    []string{"10", "0"},
+	}
+
+	fixtures := map[string]string{
+		"camelCase": `{"time":"1609459200","formattedTime":"Jan 1, 2021","formattedAxisTime":"Jan 1",` +
+			`"value":[10,0],"hasData":[true,false],"formattedValue":["10","0"]}`,
+		"snake_case": `{"time":"1609459200","formatted_time":"Jan 1, 2021","formatted_axis_time":"Jan 1",` +
+			`"value":[10,0],"has_data":[true,false],"formatted_value":["10","0"]}`,
+	}
+
+	for name, fixture := range fixtures {
+		t.Run(name, func(t *testing.T) {
+			out := new(Timeline)
+			require.NoError(t, json.Unmarshal([]byte(fixture), out))
+			assert.Equal(t, expected, out)
+		})
+	}
+
+	t.Run("camelCase wins", func(t *testing.T) {
+		out := new(Timeline)
+		require.NoError(t, json.Unmarshal([]byte(`{"hasData":[true],"has_data":[false]}`), out))
+		assert.Equal(t, []bool{true}, out.HasData)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		assert.Error(t, json.Unmarshal([]byte(`{"value":"10"}`), new(Timeline)))
+	})
+}
@@ -0,0 +1,230 @@
+package googletrends
+
+import (
+	"encoding/json"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// publicTrendingSearch is the curated, contract-stable JSON form of a TrendingSearch.
+// Field order defines the key order of the encoded output.
+type publicTrendingSearch struct {
+	Query    string           `json:"query"`
+	Traffic  string           `json:"traffic"`
+	Articles []*publicArticle `json:"articles"`
+}
+
+// publicArticle is the curated, contract-stable JSON form of a SearchArticle.
+type publicArticle struct {
+	Title   string `json:"title"`
+	URL     string `json:"url"`
+	Source  string `json:"source"`
+	TimeAgo string `json:"timeAgo"`
+	Snippet string `json:"snippet"`
+}
+
+// PublicJSON encodes the trending search into a curated JSON object suitable for
+// serving from a public API. Unlike json.Marshal on the struct itself, the output
+// doesn't depend on the internal json/bson tags and contains only:
+//
+//	{"query":"...","traffic":"...","articles":[{"title":"...","url":"...","source":"...","timeAgo":"...","snippet":"..."}]}
+//
+// Keys are always emitted in this order, and articles is an empty array rather
+// than null when there are none. Images are intentionally left out.
+func (t *TrendingSearch) PublicJSON() ([]byte, error) {
+	out := &publicTrendingSearch{
+		Traffic:  t.FormattedTraffic,
+		Articles: make([]*publicArticle, 0, len(t.Articles)),
+	}
+
+	if t.Title != nil {
+		out.Query = t.Title.Query
+	}
+
+	for _, a := range t.Articles {
+		if a == nil {
+			continue
+		}
+
+		out.Articles = append(out.Articles, &publicArticle{
+			Title:   a.Title,
+			URL:     a.URL,
+			Source:  a.Source,
+			TimeAgo: a.TimeAgo,
+			Snippet: a.Snippet,
+		})
+	}
+
+	return json.Marshal(out)
+}
+
+// URL returns a link to the Google Trends page of the trending search: ShareURL when
+// it is set, otherwise an explore URL for the query on the Google Trends host (or the
+// base set with WithLinkBase on the package-level client). It returns an empty string
+// for a search without a query.
+//
+// Example:
+//
+//	// "https://trends.google.com/trends/explore?q=golang" without a ShareURL
+//	fmt.Println(trend.URL())
+func (t *TrendingSearch) URL() string {
+	return std().TrendURL(t)
+}
+
+// TrendURL is like TrendingSearch.URL, using the link base of the client c.
+func (c *Client) TrendURL(t *TrendingSearch) string {
+	if len(t.ShareURL) != 0 {
+		return t.ShareURL
+	}
+
+	if t.Title == nil || len(t.Title.Query) == 0 {
+		return ""
+	}
+
+	return strings.TrimSuffix(c.linkBase, "/") + "/trends" + gSExplore + "?" +
+		url.Values{"q": {t.Title.Query}}.Encode()
+}
+
+// HasImage reports whether the trending search has an associated picture.
+func (t *TrendingSearch) HasImage() bool {
+	return t.Image != nil && len(t.Image.ImageURL) != 0
+}
+
+// ArticlesBySource groups the articles of the trending search by their Source,
+// which shows which outlets covered the trend. Articles without a source are
+// grouped under the empty key and nil articles are skipped. Articles keep their
+// original order within each group.
+func (t *TrendingSearch) ArticlesBySource() map[string][]*SearchArticle {
+	out := make(map[string][]*SearchArticle)
+	for _, a := range t.Articles {
+		if a == nil {
+			continue
+		}
+
+		out[a.Source] = append(out[a.Source], a)
+	}
+
+	return out
+}
+
+// trafficMultipliers are the magnitude suffixes of FormattedTraffic values.
+var trafficMultipliers = map[byte]float64{
+	'K': 1e3,
+	'M': 1e6,
+	'B': 1e9,
+}
+
+// TrafficValue returns FormattedTraffic as a number of searches, e.g. 500000 for
+// "500K+", 1500000 for "1.5M+" and 2000 for "2,000+". The "+" suffix is ignored,
+// so the value is a lower bound. It returns 0 for an empty or unparseable value.
+func (t *TrendingSearch) TrafficValue() int {
+	s := strings.ReplaceAll(strings.TrimSuffix(strings.TrimSpace(t.FormattedTraffic), "+"), ",", "")
+	if len(s) == 0 {
+		return 0
+	}
+
+	mult := 1.0
+	if m, ok := trafficMultipliers[strings.ToUpper(s[len(s)-1:])[0]]; ok {
+		mult = m
+		s = s[:len(s)-1]
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 {
+		return 0
+	}
+
+	return int(math.Round(v * mult))
+}
+
+// GrowthRate compares two snapshots of trending searches, e.g. from consecutive weeks,
+// and returns the percentage change of TrafficValue for every query of current, keyed
+// by Title.Query: 100 means the traffic doubled and -50 that it halved.
+//
+// Queries are matched by Title.Query, ignoring case. Queries that are new in current,
+// or whose previous traffic is 0 or unparseable, map to +Inf (math.Inf(1)), or to 0
+// when their current traffic is 0 as well. Queries only found in previous are left out,
+// as are nil entries.
+//
+// Example:
+//
+//	for query, growth := range googletrends.GrowthRate(thisWeek, lastWeek) {
+//	    if math.IsInf(growth, 1) {
+//	        fmt.Println(query, "is new")
+//	    }
+//	}
+func GrowthRate(current, previous []*TrendingSearch) map[string]float64 {
+	base := make(map[string]int, len(previous))
+	for _, v := range previous {
+		if v == nil || v.Title == nil {
+			continue
+		}
+
+		key := strings.ToLower(v.Title.Query)
+		if _, ok := base[key]; !ok {
+			base[key] = v.TrafficValue()
+		}
+	}
+
+	out := make(map[string]float64, len(current))
+	for _, v := range current {
+		if v == nil || v.Title == nil {
+			continue
+		}
+
+		cur := v.TrafficValue()
+		prev := base[strings.ToLower(v.Title.Query)]
+		switch {
+		case prev > 0:
+			out[v.Title.Query] = float64(cur-prev) / float64(prev) * 100
+		case cur > 0:
+			out[v.Title.Query] = math.Inf(1)
+		default:
+			out[v.Title.Query] = 0
+		}
+	}
+
+	return out
+}
+
+// UniqueArticles returns the articles of all searches de-duplicated by URL, in the
+// order they are first seen, for a single news view of the day's trends: the same
+// article is often listed under several trends.
+//
+// Articles without a URL can't be matched and are all kept. Nil searches and
+// articles are skipped.
+//
+// Example:
+//
+//	trends, _ := googletrends.DailyMerged(ctx, "EN", "US")
+//	for _, a := range googletrends.UniqueArticles(trends) {
+//	    fmt.Println(a.Source, a.Title)
+//	}
+func UniqueArticles(searches []*TrendingSearch) []*SearchArticle {
+	seen := make(map[string]bool)
+	out := make([]*SearchArticle, 0)
+	for _, s := range searches {
+		if s == nil {
+			continue
+		}
+
+		for _, a := range s.Articles {
+			if a == nil {
+				continue
+			}
+
+			if len(a.URL) != 0 {
+				if seen[a.URL] {
+					continue
+				}
+				seen[a.URL] = true
+			}
+
+			out = append(out, a)
+		}
+	}
+
+	return out
+}
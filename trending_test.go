@@ -0,0 +1,185 @@
+package googletrends
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrendingSearchPublicJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		search   *TrendingSearch
+		expected string
+	}{
+		{
+			name: "full search",
+			search: &TrendingSearch{
+				Title:            &SearchTitle{Query: "golang 1.23"},
+				FormattedTraffic: "100K+",
+				Image:            &SearchImage{ImageURL: "https://img.example.com/go.png"},
+				Articles: []*SearchArticle{
+					{
+						Title:   "Go 1.23 released",
+						TimeAgo: "2h ago",
+						Source:  "Go Blog",
+						Image:   &SearchImage{ImageURL: "https://img.example.com/a.png"},
+						URL:     "https://go.dev/blog/go1.23",
+						Snippet: "Iterators & more",
+					},
+					nil,
+				},
+			},
+			expected: `{"query":"golang 1.23","traffic":"100K+","articles":[{"title":"Go 1.23 released","url":"https://go.dev/blog/go1.23","source":"Go Blog","timeAgo":"2h ago","snippet":"Iterators \u0026 more"}]}`,
+		},
+		{
+			name:     "search without title and articles",
+			search:   &TrendingSearch{},
+			expected: `{"query":"","traffic":"","articles":[]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := tt.search.PublicJSON()
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(b))
+		})
+	}
+}
+
+func TestTrendingSearchArticlesBySource(t *testing.T) {
+	t.Parallel()
+
+	first := &SearchArticle{Title: "Go 1.23 released", Source: "Go Blog"}
+	second := &SearchArticle{Title: "Go 1.23 iterators", Source: "Go Blog", Image: &SearchImage{ImageURL: "https://img.example.com/a.png"}}
+	other := &SearchArticle{Title: "Go news", Source: "Example News"}
+	unknown := &SearchArticle{Title: "Untitled source"}
+
+	search := &TrendingSearch{Articles: []*SearchArticle{first, nil, other, second, unknown}}
+
+	assert.Equal(t, map[string][]*SearchArticle{
+		"Go Blog":      {first, second},
+		"Example News": {other},
+		"":             {unknown},
+	}, search.ArticlesBySource())
+	assert.Empty(t, (&TrendingSearch{}).ArticlesBySource())
+}
+
+func TestTrendingSearchURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		search   *TrendingSearch
+		expected string
+	}{
+		{
+			name: "share url",
+			search: &TrendingSearch{
+				Title:    &SearchTitle{Query: "golang"},
+				ShareURL: "https://trends.google.com/trends/trendingsearches/daily?geo=US#golang",
+			},
+			expected: "https://trends.google.com/trends/trendingsearches/daily?geo=US#golang",
+		},
+		{
+			name:     "falls back to explore url",
+			search:   &TrendingSearch{Title: &SearchTitle{Query: "go 1.23 & rust"}},
+			expected: "https://trends.google.com/trends/explore?q=go+1.23+%26+rust",
+		},
+		{
+			name:     "no query",
+			search:   &TrendingSearch{},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.search.URL())
+		})
+	}
+}
+
+func TestTrendingSearchTrafficValue(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]int{
+		"500K+":  500000,
+		"1M+":    1000000,
+		"1.5M+":  1500000,
+		"2,000+": 2000,
+		"200+":   200,
+		" 20k+ ": 20000,
+		"2B+":    2000000000,
+		"":       0,
+		"+":      0,
+		"lots":   0,
+		"-5K+":   0,
+	}
+
+	for traffic, expected := range tests {
+		t.Run(traffic, func(t *testing.T) {
+			assert.Equal(t, expected, (&TrendingSearch{FormattedTraffic: traffic}).TrafficValue())
+		})
+	}
+}
+
+func TestGrowthRate(t *testing.T) {
+	t.Parallel()
+
+	search := func(query, traffic string) *TrendingSearch {
+		return &TrendingSearch{Title: &SearchTitle{Query: query}, FormattedTraffic: traffic}
+	}
+
+	previous := []*TrendingSearch{
+		search("golang", "100K+"),
+		search("python", "200K+"),
+		search("zig", ""),
+		search("java", "50K+"),
+		nil,
+	}
+	current := []*TrendingSearch{
+		search("Golang", "200K+"),
+		search("python", "100K+"),
+		search("rust", "20K+"),
+		search("zig", "1K+"),
+		search("ocaml", ""),
+		{FormattedTraffic: "1M+"},
+	}
+
+	out := GrowthRate(current, previous)
+
+	require.Len(t, out, 5)
+	assert.InDelta(t, 100, out["Golang"], 1e-9)
+	assert.InDelta(t, -50, out["python"], 1e-9)
+	assert.True(t, math.IsInf(out["rust"], 1))
+	assert.True(t, math.IsInf(out["zig"], 1))
+	assert.Zero(t, out["ocaml"])
+	assert.NotContains(t, out, "java")
+}
+
+func TestUniqueArticles(t *testing.T) {
+	t.Parallel()
+
+	release := &SearchArticle{Title: "Go 1.23 released", URL: "https://go.dev/blog/go1.23"}
+	repost := &SearchArticle{Title: "Go 1.23 is out", URL: "https://go.dev/blog/go1.23"}
+	rust := &SearchArticle{Title: "Rust 2024", URL: "https://blog.rust-lang.org"}
+	noURL := &SearchArticle{Title: "Untitled"}
+	otherNoURL := &SearchArticle{Title: "Untitled"}
+
+	searches := []*TrendingSearch{
+		{Articles: []*SearchArticle{release, nil, noURL}},
+		nil,
+		{},
+		{Articles: []*SearchArticle{rust, repost, otherNoURL}},
+	}
+
+	assert.Equal(t, []*SearchArticle{release, noURL, rust, otherNoURL}, UniqueArticles(searches))
+	assert.Empty(t, UniqueArticles(nil))
+}
@@ -43,10 +43,14 @@
 // # Thread Safety
 //
 // The package-level client is safe for concurrent use. Category and location caches
-// are protected by read-write mutexes.
+// are protected by read-write mutexes. Configure may be called at any time to replace
+// the package-level client; requests in flight complete on the previous one.
 package googletrends
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"sort"
 	"strconv"
 	"strings"
@@ -54,6 +58,9 @@ import (
 
 // API endpoint constants define the base URLs and paths for Google Trends API requests.
 const (
+	// gHost is the Google Trends host that relative links like RankedKeyword.Link point to.
+	gHost = "https://trends.google.com"
+
 	// gAPI is the base URL for the Google Trends API.
 	gAPI = "https://trends.google.com/trends/api"
 
@@ -78,10 +85,24 @@ const (
 	// gSAutocomplete is the endpoint path for keyword autocomplete suggestions.
 	gSAutocomplete = "/autocomplete"
 
+	// gSDaily is the endpoint path of the legacy daily trending searches API.
+	// It is flakier than gBatchExecute but reports traffic and articles.
+	gSDaily = "/dailytrends"
+
 	// gBatchExecute is the new API endpoint for batch execute requests.
 	// This endpoint is used by the DailyNew and DailyTrendingSearchNew functions.
 	gBatchExecute = "https://trends.google.com/_/TrendsUi/data/batchexecute"
 
+	// paramFReq is the batch execute form key carrying the JSON-encoded RPC calls.
+	paramFReq = "f.req"
+
+	// rpcTrendingNow is the default batch execute RPC ID returning trending searches,
+	// see WithBatchRPCID.
+	rpcTrendingNow = "i0OFE"
+
+	// trendingNowHours is the time window in hours requested from rpcTrendingNow.
+	trendingNowHours = 48
+
 	// paramHl is the query parameter key for host language (e.g., "EN", "RU").
 	paramHl = "hl"
 
@@ -97,8 +118,91 @@ const (
 	// paramToken is the query parameter key for widget authentication token.
 	paramToken = "token"
 
+	// paramGeo is the query parameter key for the autocomplete and daily trends location.
+	paramGeo = "geo"
+
+	// paramQuery, paramDate and paramProperty are the explore link query parameter keys
+	// for the compared keywords, their time ranges and the Google property.
+	paramQuery    = "q"
+	paramDate     = "date"
+	paramProperty = "gprop"
+
+	// defaultExploreTime is the time range the web UI explores when a link sets none.
+	defaultExploreTime = "today 12-m"
+
+	// paramNS is the query parameter key the legacy daily trends API requires.
+	paramNS = "ns"
+
+	// paramEndDate is the query parameter key for the last day of legacy daily trends.
+	paramEndDate = "ed"
+
+	// dailyDateLayout is the layout of legacy daily trends dates.
+	dailyDateLayout = "20060102"
+
+	// dailyRetentionDays is how many past days the legacy daily trends API serves.
+	dailyRetentionDays = 30
+
+	// paramFirstItem is the legacy pagination key for the first item index.
+	paramFirstItem = "fi"
+
+	// paramFirstSort is the legacy pagination key for the first sort index.
+	paramFirstSort = "fs"
+
+	// paramResultsIndex is the legacy pagination key for the results index.
+	paramResultsIndex = "ri"
+
+	// paramResultsSize is the legacy pagination key for the number of results.
+	paramResultsSize = "rs"
+
+	// maxComparisonItems is the maximum number of keywords Google Trends compares at once.
+	maxComparisonItems = 5
+
 	// compareDataMode specifies the data mode for comparison requests.
 	compareDataMode = "PERCENTAGES"
+
+	// timeAll is the time range covering all available data (2004 to present).
+	timeAll = "all"
+
+	// resolutionHour is the hourly time resolution of widget data.
+	resolutionHour = "HOUR"
+
+	// resolutionDay is the daily time resolution of widget data.
+	resolutionDay = "DAY"
+
+	// resolutionWeek is the weekly time resolution of widget data.
+	resolutionWeek = "WEEK"
+
+	// resolutionMonth is the monthly time resolution of widget data.
+	resolutionMonth = "MONTH"
+
+	// resolutionDMA is the US media market (designated market area) resolution of geo data.
+	resolutionDMA = "DMA"
+
+	// resolutionCountry is the per-country resolution of worldwide geo data.
+	resolutionCountry = "COUNTRY"
+
+	// keywordTypeQuery is the keyword type of plain search queries.
+	keywordTypeQuery = "QUERY"
+
+	// keywordTypeEntity is the keyword type of Knowledge Graph entities.
+	keywordTypeEntity = "ENTITY"
+
+	// midPrefixFreebase and midPrefixGoogle are the prefixes of Knowledge Graph MIDs.
+	midPrefixFreebase = "/m/"
+	midPrefixGoogle   = "/g/"
+
+	// minTokenLen is the length below which a widget token is considered truncated.
+	// Tokens issued by Explore are much longer.
+	minTokenLen = 16
+
+	// locationUS is the country code of the United States, the only country with DMAs.
+	locationUS = "US"
+
+	// metricTop is the related searches metric for the top rankings.
+	metricTop = "TOP"
+
+	// metricRising is the related searches metric for the rising rankings.
+	metricRising = "RISING"
 )
 
 // WidgetType represents the type of a Google Trends widget.
@@ -129,18 +233,21 @@ const (
 // defaultParams contains the default query parameters used for API requests.
 // These values are copied and can be overridden for specific requests.
 var defaultParams = map[string]string{
-	paramTZ:  "0",   // Timezone offset (UTC)
-	paramCat: "all", // Category filter (all categories)
-	"fi":     "0",   // First item index
-	"fs":     "0",   // First sort index
-	paramHl:  "EN",  // Host language (English)
-	"ri":     "300", // Results index
-	"rs":     "20",  // Results size
+	paramTZ:           "0",   // Timezone offset (UTC)
+	paramCat:          "all", // Category filter (all categories)
+	paramFirstItem:    "0",   // First item index
+	paramFirstSort:    "0",   // First sort index
+	paramHl:           "EN",  // Host language (English)
+	paramResultsIndex: "300", // Results index
+	paramResultsSize:  "20",  // Results size
 }
 
 // TrendingSearchDays represents a collection of trending searches grouped by date.
-// This structure is returned by DailyTrendingSearchNew and organizes trends by day.
+// This structure is returned by DailyTrendingSearchNew and DailyDays and organizes trends by day.
 type TrendingSearchDays struct {
+	// Date is the day in the "20060102" format. It is set by DailyDays only.
+	Date string `json:"date" bson:"date"`
+
 	// FormattedDate is a human-readable date string (e.g., "Today", "Yesterday", "Nov 25").
 	FormattedDate string `json:"formattedDate" bson:"formatted_date"`
 
@@ -162,6 +269,11 @@ type TrendingSearch struct {
 
 	// Articles contains news articles related to this trending search.
 	Articles []*SearchArticle `json:"articles" bson:"articles"`
+
+	// ShareURL deep-links to the Google Trends page of the trending search.
+	// It is only reported by the legacy daily trends API and is empty otherwise;
+	// use URL for a link that is always set.
+	ShareURL string `json:"shareUrl" bson:"share_url"`
 }
 
 // SearchTitle represents the query string for a trending search.
@@ -228,6 +340,68 @@ type ExploreRequest struct {
 	// Property specifies the Google property to search.
 	// Valid values: "" (web search), "youtube", "news", "froogle" (shopping), "images".
 	Property string `json:"property" bson:"property"`
+
+	// Geo is the geographic location code shared by all comparison items, like the
+	// single location selector of the web UI. Explore applies it to every item with
+	// an empty Geo; a Geo set on an item takes precedence.
+	Geo string `json:"geo,omitempty" bson:"geo"`
+}
+
+// CacheKey returns a stable SHA-256 hex digest of the normalized request,
+// suitable for memoizing Explore results. Requests that Explore would send
+// identically get the same key: "+" in Time reads as a space, the request-level Geo
+// is applied to items without one, Geo is normalized with NormalizeGeo and an empty
+// KeywordType reads as the detected one. Keywords are taken as is, so " go" and "go"
+// get different keys.
+//
+// The order of ComparisonItems is part of the key. Widgets and timeline values
+// are indexed by comparison order, so the same keywords in a different order
+// produce a different response and must not share a cache entry.
+//
+// CacheKey doesn't modify the request.
+func (r *ExploreRequest) CacheKey() string {
+	return r.cacheKey(false)
+}
+
+// cacheKey is like CacheKey, with query keywords normalized as by
+// WithKeywordNormalization if normalizeKeywords is true.
+func (r *ExploreRequest) cacheKey(normalizeKeywords bool) string {
+	// encoding a struct of strings, ints and bools can't fail
+	b, _ := json.Marshal(r.normalized(normalizeKeywords))
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// normalized returns a copy of r in the form Explore sends it. The request-level Geo
+// is moved to the items without one, as google doesn't know the field. With
+// normalizeKeywords, plain query keywords are trimmed and lowercased.
+func (r *ExploreRequest) normalized(normalizeKeywords bool) *ExploreRequest {
+	req := *r
+	req.Geo = ""
+
+	req.ComparisonItems = make([]*ComparisonItem, 0, len(r.ComparisonItems))
+	for _, v := range r.ComparisonItems {
+		if v == nil {
+			req.ComparisonItems = append(req.ComparisonItems, nil)
+			continue
+		}
+
+		item := *v
+		// hook for using incorrect `time` request (backward compatibility)
+		item.Time = strings.ReplaceAll(item.Time, "+", " ")
+		if len(item.Geo) == 0 {
+			item.Geo = r.Geo
+		}
+		item.Geo = NormalizeGeo(item.Geo)
+		item.KeywordType = item.keywordType()
+		if normalizeKeywords && item.KeywordType == keywordTypeQuery {
+			item.Keyword = strings.ToLower(strings.TrimSpace(item.Keyword))
+		}
+		req.ComparisonItems = append(req.ComparisonItems, &item)
+	}
+
+	return &req
 }
 
 // ComparisonItem represents a single keyword for comparison in an ExploreRequest.
@@ -267,6 +441,25 @@ type ComparisonItem struct {
 	// EndTime is an alternative way to specify the end of the time range.
 	// Format: Unix timestamp in seconds.
 	EndTime string `json:"endTime" bson:"end_time"`
+
+	// KeywordType tells whether Keyword is a plain search query ("QUERY") or a
+	// Knowledge Graph entity MID ("ENTITY"), such as the Mid of a KeywordTopic.
+	// When empty, Explore sends "ENTITY" for keywords that look like a MID
+	// ("/m/..." or "/g/...") and "QUERY" otherwise.
+	KeywordType string `json:"keywordType,omitempty" bson:"keyword_type"`
+}
+
+// keywordType returns the KeywordType of the item, detecting it from Keyword when empty.
+func (i *ComparisonItem) keywordType() string {
+	if len(i.KeywordType) != 0 {
+		return i.KeywordType
+	}
+
+	if strings.HasPrefix(i.Keyword, midPrefixFreebase) || strings.HasPrefix(i.Keyword, midPrefixGoogle) {
+		return keywordTypeEntity
+	}
+
+	return keywordTypeQuery
 }
 
 // ExploreCatTree represents a hierarchical tree of Google Trends categories.
@@ -333,6 +526,72 @@ type ExploreWidget struct {
 	Request *WidgetResponse `json:"request" bson:"request"`
 }
 
+// HasValidToken reports whether the widget carries a plausible token: not empty and
+// not suspiciously short, as happens when a stored token was truncated. The data
+// functions return ErrMissingToken for widgets without a valid token instead of
+// sending a request that is bound to fail.
+func (w *ExploreWidget) HasValidToken() bool {
+	return len(w.Token) >= minTokenLen
+}
+
+// ResolvedTimeRange returns the concrete time range Google resolved the requested one
+// to, e.g. "2023-10-14 2024-10-14" for "today 12-m", so charts can be labeled with the
+// exact span of the data. The relative range as requested is kept in the
+// OriginalTimeRangeForExploreURL field of the comparison items.
+//
+// The range is read from the widget request: its Time for TIMESERIES widgets, the first
+// comparison item for GEO_MAP widgets and the restriction for related widgets. It returns
+// an empty string when the widget carries no time range.
+func (w *ExploreWidget) ResolvedTimeRange() string {
+	if w.Request == nil {
+		return ""
+	}
+
+	if len(w.Request.Time) != 0 {
+		return w.Request.Time
+	}
+
+	for _, v := range w.Request.CompItem {
+		if v != nil && len(v.Time) != 0 {
+			return v.Time
+		}
+	}
+
+	return w.Request.Restriction.Time
+}
+
+// KeywordIndex returns the comparison index of keyword in the widget, i.e. which slot
+// of the Value slices of timeline points and regions holds its data. Keywords are
+// compared ignoring case and surrounding spaces, and entity MIDs match as well.
+//
+// The index is read from the comparison items of the widget request, so it stays
+// right even if Google reorders them. Related widgets hold a single keyword in their
+// restriction, whose index is the order of the widget (e.g. 1 for "RELATED_QUERIES_1").
+// The boolean result is false when the widget doesn't compare keyword.
+//
+// Example:
+//
+//	if i, ok := timeWidget.KeywordIndex("python"); ok {
+//	    fmt.Println(timeline[0].Value[i])
+//	}
+func (w *ExploreWidget) KeywordIndex(keyword string) (int, bool) {
+	if w.Request == nil {
+		return 0, false
+	}
+
+	for i, v := range w.Request.CompItem {
+		if v != nil && v.ComplexKeywordsRestriction.matches(keyword) {
+			return i, true
+		}
+	}
+
+	if len(w.Request.CompItem) == 0 && w.Request.Restriction.ComplexKeywordsRestriction.matches(keyword) {
+		return widgetOrder(w.ID), true
+	}
+
+	return 0, false
+}
+
 // ExploreResponse is a slice of ExploreWidget pointers returned by the Explore function.
 // It implements sort.Interface for sorting widgets by their order index.
 type ExploreResponse []*ExploreWidget
@@ -433,6 +692,96 @@ func (e ExploreResponse) GetWidgetsByType(t WidgetType) ExploreResponse {
 	return out
 }
 
+// Merge returns a new ExploreResponse containing the widgets of e followed by
+// the widgets of other, sorted by their index suffix like Sort.
+// The receiver and other are left unmodified.
+//
+// Widgets are de-duplicated by Token, then by ID: when the same token or the same ID
+// appears more than once, the first occurrence (from e, then other) is kept, so
+// GetWidgetsByType doesn't depend on the merge order. Widgets with an empty token
+// are only de-duplicated by ID.
+//
+// Example:
+//
+//	business, _ := googletrends.Explore(ctx, businessReq, "EN")
+//	tech, _ := googletrends.Explore(ctx, techReq, "EN")
+//	all := business.Merge(tech)
+func (e ExploreResponse) Merge(other ExploreResponse) ExploreResponse {
+	out := make(ExploreResponse, 0, len(e)+len(other))
+	seen := make(map[string]struct{}, len(e)+len(other))
+	for _, v := range append(e[:len(e):len(e)], other...) {
+		if v == nil {
+			continue
+		}
+
+		if len(v.Token) != 0 {
+			if _, ok := seen[v.Token]; ok {
+				continue
+			}
+			seen[v.Token] = struct{}{}
+		}
+
+		out = append(out, v)
+	}
+
+	out = out.Deduplicate()
+	sort.Stable(out)
+
+	return out
+}
+
+// HasDuplicates reports whether two or more widgets of e share the same ID.
+func (e ExploreResponse) HasDuplicates() bool {
+	seen := make(map[string]struct{}, len(e))
+	for _, v := range e {
+		if v == nil {
+			continue
+		}
+
+		if _, ok := seen[v.ID]; ok {
+			return true
+		}
+		seen[v.ID] = struct{}{}
+	}
+
+	return false
+}
+
+// Deduplicate returns a new ExploreResponse without widgets whose ID already appeared
+// earlier in e, keeping the first occurrence and the original order. Google occasionally
+// returns the same widget twice, which would otherwise show up twice in GetWidgetsByType.
+// Explore deduplicates its responses already, and Merge deduplicates its result.
+func (e ExploreResponse) Deduplicate() ExploreResponse {
+	out := make(ExploreResponse, 0, len(e))
+	seen := make(map[string]struct{}, len(e))
+	for _, v := range e {
+		if v == nil {
+			continue
+		}
+
+		if _, ok := seen[v.ID]; ok {
+			continue
+		}
+		seen[v.ID] = struct{}{}
+
+		out = append(out, v)
+	}
+
+	return out
+}
+
+// withRequests returns the widgets of e that have a request payload, see WithSkipEmptyWidgets.
+func (e ExploreResponse) withRequests() ExploreResponse {
+	out := make(ExploreResponse, 0, len(e))
+	for _, v := range e {
+		if v != nil && v.Request != nil {
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
 // WidgetResponse contains the request parameters for fetching widget data.
 // This structure is embedded in ExploreWidget and contains system-level
 // configuration for each type of trends search.
@@ -504,6 +853,19 @@ type KeywordsRestriction struct {
 	Keyword []*KeywordRestriction `json:"keyword" bson:"keyword"`
 }
 
+// matches reports whether any keyword of the restriction equals keyword,
+// ignoring case and surrounding spaces.
+func (r KeywordsRestriction) matches(keyword string) bool {
+	keyword = strings.TrimSpace(keyword)
+	for _, v := range r.Keyword {
+		if v != nil && strings.EqualFold(strings.TrimSpace(v.Value), keyword) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // KeywordRestriction defines a single keyword filter restriction.
 // It specifies both the type of restriction and the value to filter by.
 type KeywordRestriction struct {
@@ -529,12 +891,15 @@ type RequestOptions struct {
 
 // multilineOut is an internal structure for unmarshaling interest over time API responses.
 type multilineOut struct {
-	Default multiline `json:"default" bson:"default"`
+	Default *multiline `json:"default" bson:"default"`
 }
 
 // multiline is an internal structure containing timeline data.
 type multiline struct {
 	TimelineData []*Timeline `json:"timelineData" bson:"timeline_data"`
+
+	// Averages holds the overall average of every keyword over the whole range.
+	Averages []int `json:"averages" bson:"averages"`
 }
 
 // Timeline represents a single data point in the interest over time chart.
@@ -561,11 +926,19 @@ type Timeline struct {
 
 	// FormattedValue contains display-ready strings for each value.
 	FormattedValue []string `json:"formattedValue" bson:"formatted_value"`
+
+	// Filled marks, per keyword, values that were not returned by Google but filled in
+	// by FillTimelineGaps or InterestOverTimeFilled. It is nil for unmodified points.
+	Filled []bool `json:"filled,omitempty" bson:"filled,omitempty"`
 }
 
+// Timelines is interest over time data, as returned by InterestOverTime, with
+// methods working on the whole series. Convert a []*Timeline with Timelines(data).
+type Timelines []*Timeline
+
 // geoOut is an internal structure for unmarshaling interest by location API responses.
 type geoOut struct {
-	Default geo `json:"default" bson:"default"`
+	Default *geo `json:"default" bson:"default"`
 }
 
 // geo is an internal structure containing geographic map data.
@@ -600,9 +973,13 @@ type GeoMap struct {
 	HasData []bool `json:"hasData" bson:"has_data"`
 }
 
+// GeoMaps is interest by location data, as returned by InterestByLocation, with
+// methods working on the whole map. Convert a []*GeoMap with GeoMaps(data).
+type GeoMaps []*GeoMap
+
 // relatedOut is an internal structure for unmarshaling related searches API responses.
 type relatedOut struct {
-	Default relatedList `json:"default" bson:"default"`
+	Default *relatedList `json:"default" bson:"default"`
 }
 
 // relatedList is an internal structure containing ranked keyword lists.
@@ -615,6 +992,16 @@ type rankedList struct {
 	Keywords []*RankedKeyword `json:"rankedKeyword" bson:"keywords"`
 }
 
+// dailyOut is an internal structure for unmarshaling legacy daily trends API responses.
+type dailyOut struct {
+	Default *dailyList `json:"default" bson:"default"`
+}
+
+// dailyList is an internal structure containing trending searches grouped by day.
+type dailyList struct {
+	Days []*TrendingSearchDays `json:"trendingSearchesDays" bson:"days"`
+}
+
 // searchOut is an internal structure for unmarshaling autocomplete API responses.
 type searchOut struct {
 	Default searchList `json:"default" bson:"default"`
@@ -649,6 +1036,47 @@ type RankedKeyword struct {
 
 	// Link is the Google Trends URL for exploring this related query/topic.
 	Link string `json:"link" bson:"link"`
+
+	// rising is set for keywords parsed from a RISING ranked list.
+	rising bool
+}
+
+// FullLink returns Link as an absolute URL by prefixing it with the Google Trends host
+// (or the base set with WithLinkBase on the package-level client). Links that are
+// already absolute are returned unchanged, and an empty Link yields an empty string.
+//
+// Example:
+//
+//	// "/trends/explore?q=golang+tutorial" becomes
+//	// "https://trends.google.com/trends/explore?q=golang+tutorial"
+//	fmt.Println(keyword.FullLink())
+func (k *RankedKeyword) FullLink() string {
+	return std().FullLink(k)
+}
+
+// FullLink is like RankedKeyword.FullLink, using the link base of the client c.
+func (c *Client) FullLink(k *RankedKeyword) string {
+	if len(k.Link) == 0 || strings.HasPrefix(k.Link, "http://") || strings.HasPrefix(k.Link, "https://") {
+		return k.Link
+	}
+
+	return strings.TrimSuffix(c.linkBase, "/") + "/" + strings.TrimPrefix(k.Link, "/")
+}
+
+// isRising reports whether the keyword is a rising entry: it comes from a RISING ranked
+// list, or its FormattedValue is a growth percentage like "+250%" rather than a 0-100
+// interest value. Top values such as "<1" or a localized "1,000" are not rising.
+func (k *RankedKeyword) isRising() bool {
+	return k.rising || strings.HasPrefix(k.FormattedValue, "+") && strings.HasSuffix(k.FormattedValue, "%")
+}
+
+// term returns the query string for related queries or the topic title for related topics.
+func (k *RankedKeyword) term() string {
+	if len(k.Query) != 0 {
+		return k.Query
+	}
+
+	return k.Topic.Title
 }
 
 // KeywordTopic represents a Google Knowledge Graph topic or entity.
@@ -0,0 +1,53 @@
+package googletrends
+
+// version is the release version of this package.
+const version = "1.7.0"
+
+// Capability names reported by Capabilities.
+const (
+	// CapabilityBatchDaily reports daily trends via the batch execute API (DailyNew).
+	CapabilityBatchDaily = "batch_daily"
+
+	// CapabilityRealtime reports realtime trends via the legacy realtime API.
+	CapabilityRealtime = "realtime"
+
+	// CapabilityLegacyCategories reports the legacy category picker (ExploreCategories).
+	CapabilityLegacyCategories = "legacy_categories"
+
+	// CapabilityLegacyLocations reports the legacy geo picker (ExploreLocations).
+	CapabilityLegacyLocations = "legacy_locations"
+
+	// CapabilityExplore reports the explore and widget data APIs
+	// (Explore, InterestOverTime, InterestByLocation, Related).
+	CapabilityExplore = "explore"
+
+	// CapabilityAutocomplete reports the autocomplete API (Search).
+	CapabilityAutocomplete = "autocomplete"
+)
+
+// Version returns the release version of this package.
+// Include it in bug reports, since Google Trends changes its API without notice
+// and fixes are tied to specific releases.
+func Version() string {
+	return version
+}
+
+// Capabilities reports which Google Trends endpoints this version of the package supports.
+// Keys are the Capability constants; a false value means the endpoint is known
+// but not wired in this version. A new map is returned on every call.
+//
+// Example:
+//
+//	if googletrends.Capabilities()[googletrends.CapabilityRealtime] {
+//	    // use realtime trends
+//	}
+func Capabilities() map[string]bool {
+	return map[string]bool{
+		CapabilityBatchDaily:       true,
+		CapabilityRealtime:         false,
+		CapabilityLegacyCategories: true,
+		CapabilityLegacyLocations:  true,
+		CapabilityExplore:          true,
+		CapabilityAutocomplete:     true,
+	}
+}